@@ -13,18 +13,45 @@ func main() {
 		os.Exit(1)
 	}
 
+	var run func([]string) error
 	switch os.Args[1] {
 	case "scan":
-		if err := cli.Run(os.Args[2:]); err != nil {
-			fmt.Fprintln(os.Stderr, "error:", err)
-			os.Exit(1)
-		}
+		run = cli.Run
+	case "probe":
+		run = cli.RunProbe
+	case "diff":
+		run = cli.RunDiff
+	case "history":
+		run = cli.RunHistory
+	case "report":
+		run = cli.RunReport
+	case "watch":
+		run = cli.RunWatch
+	case "serve":
+		run = cli.RunServe
+	case "platforms":
+		run = cli.RunPlatforms
+	case "doctor":
+		run = cli.RunDoctor
 	default:
 		printUsage()
 		os.Exit(1)
 	}
+
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(cli.ExitCode(err))
+	}
 }
 
 func printUsage() {
 	fmt.Fprintln(os.Stderr, "usage: model-scout scan [flags]")
+	fmt.Fprintln(os.Stderr, "       model-scout probe [flags] model...")
+	fmt.Fprintln(os.Stderr, "       model-scout diff [flags] old-results new-results")
+	fmt.Fprintln(os.Stderr, "       model-scout history [flags]")
+	fmt.Fprintln(os.Stderr, "       model-scout report [flags]")
+	fmt.Fprintln(os.Stderr, "       model-scout watch [flags]")
+	fmt.Fprintln(os.Stderr, "       model-scout serve [flags]")
+	fmt.Fprintln(os.Stderr, "       model-scout platforms [flags]")
+	fmt.Fprintln(os.Stderr, "       model-scout doctor [flags]")
 }
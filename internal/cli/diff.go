@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/diff"
+	"github.com/NERVEbing/model-scout/internal/notify"
+	"github.com/NERVEbing/model-scout/internal/output"
+)
+
+// ErrRegression is returned by diff --fail-on-regression when a model became
+// unavailable or an available model disappeared.
+var ErrRegression = errors.New("availability regressed")
+
+// ExitCode maps an error returned by a command to the process exit status.
+func ExitCode(err error) int {
+	if errors.Is(err, ErrRegression) {
+		return 2
+	}
+	return 1
+}
+
+type diffReport struct {
+	Summary    map[string]int `json:"summary" yaml:"summary"`
+	Regression bool           `json:"regression" yaml:"regression"`
+	Changes    []diff.Change  `json:"changes" yaml:"changes"`
+}
+
+func RunDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	outFormat := flags.String("out", "table", "output format: table, json, yaml or markdown")
+	outputFile := flags.String("output-file", "", "output file path")
+	failOnRegression := flags.Bool("fail-on-regression", false, "exit with status 2 if availability regressed")
+	configFile := flags.String("config", "", "config file path (YAML)")
+	sendNotifications := flags.Bool("notify", false, "send the changes to the notifiers in --config")
+
+	paths, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) != 2 {
+		return errors.New("usage: model-scout diff [flags] old-results new-results")
+	}
+	write, err := diffFormatter(*outFormat)
+	if err != nil {
+		return err
+	}
+	var notifiers []*notify.Notifier
+	if *sendNotifications {
+		if notifiers, err = configNotifiers(*configFile); err != nil {
+			return err
+		}
+	}
+
+	before, err := output.LoadResults(paths[0])
+	if err != nil {
+		return err
+	}
+	after, err := output.LoadResults(paths[1])
+	if err != nil {
+		return err
+	}
+
+	changes := diff.Compare(before, after)
+	if err := writeOutput(*outputFile, func(w io.Writer) error {
+		return write(w, changes)
+	}); err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		if err := notify.Send(context.Background(), notifiers, notify.Event{Time: time.Now(), Changes: changes}); err != nil {
+			return err
+		}
+	}
+	if *failOnRegression && diff.HasRegression(changes) {
+		return ErrRegression
+	}
+	return nil
+}
+
+func configNotifiers(configFile string) ([]*notify.Notifier, error) {
+	if configFile == "" {
+		return nil, errors.New("--notify requires --config with notifiers")
+	}
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Notifiers) == 0 {
+		return nil, fmt.Errorf("no notifiers configured in %s", configFile)
+	}
+	return notify.NewAll(cfg.Notifiers)
+}
+
+func diffFormatter(format string) (func(io.Writer, []diff.Change) error, error) {
+	switch strings.ToLower(format) {
+	case "table":
+		return output.WriteDiffTable, nil
+	case "markdown":
+		return output.WriteDiffMarkdown, nil
+	case "json", "yaml":
+		writePayload := output.WriteJSON
+		if strings.EqualFold(format, "yaml") {
+			writePayload = output.WriteYAML
+		}
+		return func(w io.Writer, changes []diff.Change) error {
+			if changes == nil {
+				changes = []diff.Change{}
+			}
+			return writePayload(w, diffReport{
+				Summary:    diff.Count(changes),
+				Regression: diff.HasRegression(changes),
+				Changes:    changes,
+			})
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
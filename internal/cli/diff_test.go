@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeResultsFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := writeResultsFile(t, dir, "old.json", `[
+  {"platform":"dashscope","model":"qwen-plus","status":"ok","available":true},
+  {"platform":"dashscope","model":"qwen-max","status":"ok","available":true}
+]`)
+	newPath := writeResultsFile(t, dir, "new.yaml", `
+- platform: dashscope
+  model: qwen-plus
+  status: ok
+  available: true
+- platform: dashscope
+  model: qwen-max
+  status: fail
+  available: false
+  reason: 403 Forbidden
+`)
+	outputPath := filepath.Join(dir, "diff.json")
+
+	if err := RunDiff([]string{oldPath, newPath, "--out", "json", "--output-file", outputPath}); err != nil {
+		t.Fatalf("expected no error without --fail-on-regression, got %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	var report diffReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if !report.Regression || len(report.Changes) != 1 || report.Changes[0].Model != "qwen-max" || report.Summary["now-unavailable"] != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	err = RunDiff([]string{"--fail-on-regression", "--output-file", outputPath, oldPath, newPath})
+	if !errors.Is(err, ErrRegression) || ExitCode(err) != 2 {
+		t.Fatalf("expected regression error with exit code 2, got %v", err)
+	}
+	if err := RunDiff([]string{"--fail-on-regression", "--output-file", outputPath, newPath, oldPath}); err != nil {
+		t.Fatalf("expected no regression in reverse, got %v", err)
+	}
+}
+
+func TestRunDiffArguments(t *testing.T) {
+	if err := RunDiff([]string{"only-one.json"}); err == nil {
+		t.Fatalf("expected usage error")
+	}
+	if err := RunDiff([]string{"--out", "xml", "a.json", "b.json"}); err == nil {
+		t.Fatalf("expected unsupported format error")
+	}
+}
+
+func TestRunDiffNotify(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	oldPath := writeResultsFile(t, dir, "old.json", `[{"platform":"dashscope","model":"qwen-max","status":"ok","available":true}]`)
+	newPath := writeResultsFile(t, dir, "new.json", `[{"platform":"dashscope","model":"qwen-max","status":"fail","reason":"403 Forbidden"}]`)
+	configPath := writeResultsFile(t, dir, "config.yaml", "notifiers:\n  - type: slack\n    url: "+server.URL+"\n")
+
+	if err := RunDiff([]string{"--notify", "--output-file", filepath.Join(dir, "diff.txt"), oldPath, newPath}); err == nil {
+		t.Fatalf("expected --notify to require --config")
+	}
+	if err := RunDiff([]string{"--notify", "--config", configPath, "--output-file", filepath.Join(dir, "diff.txt"), oldPath, newPath}); err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if len(bodies) != 1 || !strings.Contains(bodies[0], "now-unavailable: dashscope/qwen-max (fail: 403 Forbidden)") {
+		t.Fatalf("unexpected notifications: %q", bodies)
+	}
+	if err := RunDiff([]string{"--notify", "--config", configPath, "--output-file", filepath.Join(dir, "diff.txt"), oldPath, oldPath}); err != nil || len(bodies) != 1 {
+		t.Fatalf("expected no notification without changes, got %v, %q", err, bodies)
+	}
+}
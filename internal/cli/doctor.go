@@ -0,0 +1,319 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/output"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// Doctor check statuses.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// certWarnWindow is how close to expiry a certificate is reported.
+const certWarnWindow = 14 * 24 * time.Hour
+
+type doctorCheck struct {
+	Platform string `json:"platform" yaml:"platform"`
+	Check    string `json:"check" yaml:"check"`
+	Status   string `json:"status" yaml:"status"`
+	Detail   string `json:"detail" yaml:"detail"`
+	// Problem classifies a failed key check, such as invalid_key.
+	Problem string `json:"problem,omitempty" yaml:"problem,omitempty"`
+	Hint    string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+// doctor checks that platforms can be reached and their keys used. proxy and
+// transport default to each platform's configured transport; the hooks are
+// replaced in tests.
+type doctor struct {
+	timeout    time.Duration
+	proxy      func(*http.Request) (*url.URL, error)
+	transport  http.RoundTripper
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+}
+
+func RunDoctor(args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	opts := &engineOptions{}
+	flags.StringVar(&opts.platformName, "platform", "", "platforms to check (comma-separated; default: every platform with a key configured)")
+	flags.Var(&opts.apiKeys, "api-key", "api key (repeatable)")
+	flags.StringVar(&opts.keyFile, "key-file", "", "file with api keys, one per line as 'key' or 'label key'")
+	flags.StringVar(&opts.configFile, "config", "", "config file path (YAML)")
+	flags.DurationVar(&opts.timeout, "timeout", 15*time.Second, "timeout of each check")
+	registerTransportFlags(flags, &opts.transport)
+	outFormat := flags.String("out", "table", "output format: table, json or yaml")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	var write func(io.Writer, []doctorCheck) error
+	switch strings.ToLower(*outFormat) {
+	case "table":
+		write = writeDoctorTable
+	case "json":
+		write = func(w io.Writer, checks []doctorCheck) error { return output.WriteJSON(w, checks) }
+	case "yaml":
+		write = func(w io.Writer, checks []doctorCheck) error { return output.WriteYAML(w, checks) }
+	default:
+		return fmt.Errorf("unsupported output format: %s", *outFormat)
+	}
+	if opts.configFile != "" {
+		cfg, err := config.Load(opts.configFile)
+		if err != nil {
+			return err
+		}
+		opts.config = cfg
+	}
+	names := splitList(opts.platformName)
+	if len(names) == 0 {
+		names = configuredPlatforms(opts.config)
+	}
+	if len(names) == 0 {
+		return errors.New("no platform has a key configured; pass --platform or set a key variable listed by 'model-scout platforms'")
+	}
+	if len(names) > 1 && (len(opts.apiKeys) > 0 || opts.keyFile != "") {
+		return errors.New("--api-key and --key-file can only be used with a single platform")
+	}
+
+	d := doctor{timeout: opts.timeout, lookupHost: net.DefaultResolver.LookupHost}
+	var checks []doctorCheck
+	for _, name := range names {
+		checks = append(checks, d.run(context.Background(), opts, name)...)
+	}
+	if err := write(os.Stdout, checks); err != nil {
+		return err
+	}
+	failed := 0
+	for _, check := range checks {
+		if check.Status == checkFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d failed checks", failed)
+	}
+	return nil
+}
+
+// configuredPlatforms returns the platforms with a key in the environment or
+// the config file.
+func configuredPlatforms(cfg *config.Config) []string {
+	var names []string
+	for _, descriptor := range platform.Descriptors() {
+		if key, _ := descriptor.EnvKey(); key != "" || len(cfg.Platform(descriptor.Name).Keys) > 0 {
+			names = append(names, descriptor.Name)
+		}
+	}
+	return names
+}
+
+// run checks one platform: proxy settings, DNS, TLS reachability of its base
+// URL, then every key with the cheapest authenticated call, ListModels.
+func (d doctor) run(ctx context.Context, opts *engineOptions, name string) []doctorCheck {
+	descriptor, err := platform.Lookup(name)
+	if err != nil {
+		return []doctorCheck{{Platform: name, Check: "platform", Status: checkFail, Detail: err.Error()}}
+	}
+	base, err := url.Parse(descriptor.BaseURL)
+	if err != nil || base.Host == "" {
+		return []doctorCheck{{Platform: descriptor.Name, Check: "platform", Status: checkFail, Detail: fmt.Sprintf("invalid base URL %q", descriptor.BaseURL)}}
+	}
+	check := func(kind, status, detail, hint string) doctorCheck {
+		return doctorCheck{Platform: descriptor.Name, Check: kind, Status: status, Detail: detail, Hint: hint}
+	}
+
+	transportOpts := opts.transportOptions(descriptor.Name)
+	httpClient, err := platform.NewHTTPClient(d.timeout, transportOpts)
+	if err != nil {
+		return []doctorCheck{check("transport", checkFail, err.Error(), "fix --proxy, --ca-file, --client-cert and --client-key or their config entries")}
+	}
+	if d.transport == nil {
+		transport := httpClient.Transport.(*http.Transport)
+		d.transport = transport
+		d.proxy = transport.Proxy
+		if d.proxy == nil {
+			d.proxy = func(*http.Request) (*url.URL, error) { return nil, nil }
+		}
+	}
+
+	proxyCheck, proxyURL := d.checkProxy(base, transportOpts.Proxy != "")
+	proxyCheck.Platform = descriptor.Name
+	checks := []doctorCheck{proxyCheck}
+
+	if proxyURL != nil {
+		checks = append(checks, check("dns", checkSkip, "resolved by the proxy", ""))
+	} else if addrs, err := d.lookup(ctx, base.Hostname()); err != nil {
+		checks = append(checks, check("dns", checkFail, err.Error(), "the host name does not resolve; check DNS settings or set HTTPS_PROXY if only a proxy can reach it"))
+	} else {
+		checks = append(checks, check("dns", checkOK, base.Hostname()+" resolves to "+strings.Join(addrs[:min(len(addrs), 3)], ", "), ""))
+	}
+
+	tlsCheck := d.checkTLS(ctx, base, check)
+	if tlsCheck.Status == checkOK && transportOpts.InsecureSkipVerify {
+		tlsCheck.Status = checkWarn
+		tlsCheck.Hint = "certificate verification is disabled; only use insecure_skip_verify for lab gateways"
+	}
+	checks = append(checks, tlsCheck)
+
+	keys, err := opts.keys(descriptor)
+	if err != nil {
+		return append(checks, check("key", checkFail, err.Error(), "set "+strings.Join(descriptor.KeyEnv, " or ")+", or pass --api-key"))
+	}
+	for _, key := range keys {
+		kind := "key"
+		if len(keys) > 1 {
+			kind = "key " + key.label
+		}
+		if tlsCheck.Status == checkFail {
+			checks = append(checks, check(kind, checkSkip, "platform not reachable", ""))
+			continue
+		}
+		checks = append(checks, d.checkKey(ctx, opts, descriptor, key, httpClient, check(kind, "", "", "")))
+	}
+	return checks
+}
+
+// checkProxy reports the proxy used for base. configured means --proxy or
+// the config file chose it, so the environment variables do not apply.
+func (d doctor) checkProxy(base *url.URL, configured bool) (doctorCheck, *url.URL) {
+	check := doctorCheck{Check: "proxy"}
+	var set []string
+	for _, env := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy", "ALL_PROXY", "all_proxy"} {
+		if os.Getenv(env) != "" && !configured {
+			set = append(set, env)
+		}
+	}
+
+	proxyURL, err := d.proxy(&http.Request{URL: base})
+	switch {
+	case err != nil:
+		check.Status, check.Detail = checkFail, "invalid proxy setting: "+err.Error()
+		check.Hint = "fix the proxy URL in " + strings.Join(set, ", ")
+	case proxyURL != nil:
+		check.Status, check.Detail = checkOK, "via "+proxyURL.Redacted()
+	default:
+		check.Status, check.Detail = checkOK, "direct connection"
+	}
+	if configured {
+		check.Detail += " (configured)"
+	} else if len(set) > 0 && err == nil {
+		check.Detail += " (" + strings.Join(set, ", ") + " set)"
+	}
+	if !configured && proxyURL == nil && err == nil && (os.Getenv("ALL_PROXY") != "" || os.Getenv("all_proxy") != "") {
+		check.Status = checkWarn
+		check.Hint = "ALL_PROXY is ignored by model-scout; set HTTPS_PROXY or --proxy to use the proxy"
+	}
+	return check, proxyURL
+}
+
+func (d doctor) lookup(ctx context.Context, host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	return d.lookupHost(ctx, host)
+}
+
+// checkTLS requests the base URL without credentials. Any HTTP response shows
+// the platform is reachable; the status itself does not matter.
+func (d doctor) checkTLS(ctx context.Context, base *url.URL, check func(kind, status, detail, hint string) doctorCheck) doctorCheck {
+	transport := d.transport
+	if transport == nil {
+		defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
+		defaultTransport.Proxy = d.proxy
+		transport = defaultTransport
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String(), nil)
+	if err != nil {
+		return check("tls", checkFail, err.Error(), "")
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		var unknownAuthority x509.UnknownAuthorityError
+		var invalid x509.CertificateInvalidError
+		var hostname x509.HostnameError
+		switch {
+		case errors.As(err, &unknownAuthority):
+			return check("tls", checkFail, err.Error(), "the certificate is not trusted; pass the issuing CA with --ca-file or ca_file")
+		case errors.As(err, &invalid), errors.As(err, &hostname):
+			return check("tls", checkFail, err.Error(), "the certificate is invalid for this host; check for an intercepting proxy or a wrong base URL")
+		}
+		problem := platform.ClassifyAccountError(err)
+		return check("tls", checkFail, err.Error(), problem.Hint)
+	}
+	resp.Body.Close()
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return check("tls", checkWarn, "reachable over plain HTTP ("+resp.Status+")", "the key is sent unencrypted; use an https base URL")
+	}
+	cert := resp.TLS.PeerCertificates[0]
+	detail := fmt.Sprintf("%s, certificate valid until %s (%s)", tls.VersionName(resp.TLS.Version), cert.NotAfter.Format(time.DateOnly), resp.Status)
+	if time.Until(cert.NotAfter) < certWarnWindow {
+		return check("tls", checkWarn, detail, "the certificate expires soon")
+	}
+	return check("tls", checkOK, detail, "")
+}
+
+func (d doctor) checkKey(ctx context.Context, opts *engineOptions, descriptor platform.Descriptor, key apiKey, httpClient *http.Client, check doctorCheck) doctorCheck {
+	platformImpl, err := platformFactory(descriptor.Name, platform.Options{APIKey: key.key, Timeout: opts.timeout, HTTPClient: httpClient})
+	if err != nil {
+		check.Status, check.Detail = checkFail, err.Error()
+		return check
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	models, err := platformImpl.ListModels(ctx)
+	if err != nil {
+		problem := platform.ClassifyAccountError(err)
+		check.Status = checkFail
+		check.Detail = output.ShortReason(err.Error(), 120)
+		check.Problem, check.Hint = problem.Kind, problem.Hint
+		return check
+	}
+	check.Status = checkOK
+	check.Detail = fmt.Sprintf("%d models listed with %s", len(models), platform.KeyFingerprint(key.key))
+	return check
+}
+
+func writeDoctorTable(w io.Writer, checks []doctorCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLATFORM\tCHECK\tSTATUS\tDETAIL")
+	for _, check := range checks {
+		detail := check.Detail
+		if check.Problem != "" {
+			detail = check.Problem + ": " + detail
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", check.Platform, check.Check, check.Status, detail)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, check := range checks {
+		if check.Hint != "" {
+			if _, err := fmt.Fprintf(w, "%s %s: %s\n", check.Platform, check.Check, check.Hint); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -1,42 +1,30 @@
 package cli
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/NERVEbing/model-scout/internal/platform"
 )
 
 func TestParseFilters(t *testing.T) {
-	t.Run("valid eq", func(t *testing.T) {
-		filters, err := parseFilters([]string{"status=ok,active"})
+	t.Run("valid", func(t *testing.T) {
+		expr, err := parseFilters([]string{"status=ok,active", "model!=qwen-plus"})
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
-		if len(filters) != 1 {
-			t.Fatalf("expected 1 filter, got %d", len(filters))
-		}
-		filter := filters[0]
-		if filter.key != "status" {
-			t.Fatalf("expected key status, got %q", filter.key)
-		}
-		if filter.op != filterEq {
-			t.Fatalf("expected eq op")
-		}
-		if len(filter.values) != 2 || filter.values[0] != "ok" || filter.values[1] != "active" {
-			t.Fatalf("unexpected values: %#v", filter.values)
+		if expr == nil {
+			t.Fatalf("expected expression")
 		}
 	})
 
-	t.Run("valid not eq", func(t *testing.T) {
-		filters, err := parseFilters([]string{"model!=qwen-plus"})
+	t.Run("empty", func(t *testing.T) {
+		expr, err := parseFilters(nil)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
-		if len(filters) != 1 {
-			t.Fatalf("expected 1 filter, got %d", len(filters))
-		}
-		if filters[0].op != filterNotEq {
-			t.Fatalf("expected not eq op")
+		if expr != nil {
+			t.Fatalf("expected no expression")
 		}
 	})
 
@@ -130,33 +118,35 @@ func TestApplyFilters(t *testing.T) {
 			t.Fatalf("unexpected model: %s", filtered[0].Model)
 		}
 	})
-}
 
-func TestDefaultKeyEnv(t *testing.T) {
-	t.Run("dashscope default", func(t *testing.T) {
-		env, err := defaultKeyEnv("dashscope")
+	t.Run("filter expression", func(t *testing.T) {
+		filters, err := parseFilters([]string{"platform = dashscope and (status = active or not available)"})
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
-		if env != defaultDashscopeKeyEnv {
-			t.Fatalf("expected %s, got %q", defaultDashscopeKeyEnv, env)
+		filtered := applyFilters(results, filters)
+		if len(filtered) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(filtered))
 		}
 	})
+}
 
-	t.Run("deepseek default", func(t *testing.T) {
-		env, err := defaultKeyEnv("deepseek")
+func TestPlatformKeyEnv(t *testing.T) {
+	for name, want := range map[string]string{
+		"dashscope": "DASHSCOPE_API_KEY",
+		"bailian":   "DASHSCOPE_API_KEY",
+		"deepseek":  "DEEPSEEK_API_KEY",
+	} {
+		descriptor, err := platform.Lookup(name)
 		if err != nil {
-			t.Fatalf("expected no error, got %v", err)
+			t.Fatalf("lookup %s: %v", name, err)
 		}
-		if env != "DEEPSEEK_API_KEY" {
-			t.Fatalf("expected DEEPSEEK_API_KEY, got %q", env)
+		if !slices.Contains(descriptor.KeyEnv, want) {
+			t.Fatalf("%s: expected %s in %v", name, want, descriptor.KeyEnv)
 		}
-	})
+	}
 
-	t.Run("unsupported platform", func(t *testing.T) {
-		_, err := defaultKeyEnv("unknown")
-		if err == nil {
-			t.Fatalf("expected error for unsupported platform")
-		}
-	})
+	if _, err := platform.Lookup("unknown"); err == nil {
+		t.Fatalf("expected error for unsupported platform")
+	}
 }
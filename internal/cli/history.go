@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/output"
+)
+
+func RunHistory(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	dir := flags.String("history-dir", "", "history directory")
+	configFile := flags.String("config", "", "config file path (YAML)")
+	platformName := flags.String("platform", "", "only show this platform")
+	model := flags.String("model", "", "only show this model ID")
+	key := flags.String("key", "", "only show results of this key label or fingerprint")
+	since := flags.String("since", "", "only show scans at or after this time: a duration such as 24h or 7d, a date or an RFC 3339 time")
+	until := flags.String("until", "", "only show scans at or before this time (same forms as --since)")
+	outFormat := flags.String("out", "table", "output format: table, json, yaml or ndjson")
+	outputFile := flags.String("output-file", "", "output file path")
+	var filters filterExpressions
+	flags.Var(&filters, "filter", "filter results with an expression (repeatable, combined with and)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	store, err := openHistory(*dir, *configFile)
+	if err != nil {
+		return err
+	}
+	query, err := historyQuery(*platformName, *model, *key, *since, *until, time.Now())
+	if err != nil {
+		return err
+	}
+	expr, err := parseFilters(filters)
+	if err != nil {
+		return err
+	}
+	write, err := historyFormatter(*outFormat)
+	if err != nil {
+		return err
+	}
+
+	scans, err := store.Query(query)
+	if err != nil {
+		return err
+	}
+	entries := make([]history.Entry, 0)
+	for _, entry := range history.Entries(scans) {
+		if expr == nil || expr.Match(entry.ProbeResult) {
+			entries = append(entries, entry)
+		}
+	}
+	return writeOutput(*outputFile, func(w io.Writer) error {
+		return write(w, entries)
+	})
+}
+
+// openHistory opens the directory given by --history-dir, falling back to
+// history_dir in the config file.
+func openHistory(dir, configFile string) (*history.Store, error) {
+	if dir == "" && configFile != "" {
+		cfg, err := config.Load(configFile)
+		if err != nil {
+			return nil, err
+		}
+		dir = cfg.HistoryDir
+	}
+	if dir == "" {
+		return nil, errors.New("--history-dir is required (or set history_dir in --config)")
+	}
+	return history.Open(dir)
+}
+
+func historyQuery(platformName, model, key, since, until string, now time.Time) (history.Query, error) {
+	query := history.Query{
+		Platform: strings.TrimSpace(platformName),
+		Model:    strings.TrimSpace(model),
+		Key:      strings.TrimSpace(key),
+	}
+	var err error
+	if query.Since, err = parseTimeBound(since, now, false); err != nil {
+		return history.Query{}, fmt.Errorf("--since: %w", err)
+	}
+	if query.Until, err = parseTimeBound(until, now, true); err != nil {
+		return history.Query{}, fmt.Errorf("--until: %w", err)
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && query.Until.Before(query.Since) {
+		return history.Query{}, errors.New("--until is before --since")
+	}
+	return query, nil
+}
+
+// parseTimeBound accepts a duration before now (90m, 24h, 7d), a local date
+// (2006-01-02) or an RFC 3339 time. A date used as an upper bound covers the
+// whole day.
+func parseTimeBound(raw string, now time.Time, endOfDay bool) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(raw); err == nil {
+		return now.Add(-d), nil
+	}
+	if day, err := time.ParseInLocation("2006-01-02", raw, time.Local); err == nil {
+		if endOfDay {
+			return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return day, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use a duration such as 24h or 7d, a date such as 2006-01-02, or an RFC 3339 time)", raw)
+}
+
+func historyFormatter(format string) (func(io.Writer, []history.Entry) error, error) {
+	switch strings.ToLower(format) {
+	case "table":
+		return output.WriteHistoryTable, nil
+	case "json":
+		return func(w io.Writer, entries []history.Entry) error {
+			return output.WriteJSON(w, entries)
+		}, nil
+	case "yaml":
+		return func(w io.Writer, entries []history.Entry) error {
+			return output.WriteYAML(w, entries)
+		}, nil
+	case "ndjson":
+		return func(w io.Writer, entries []history.Entry) error {
+			for _, entry := range entries {
+				if err := output.WriteNDJSON(w, entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+)
+
+func TestScanRecordsHistory(t *testing.T) {
+	useFakePlatform(t)
+	dir := filepath.Join(t.TempDir(), "history")
+
+	if _, err := captureStdout(t, func() error {
+		return Run([]string{"--platform", "dashscope", "--api-key", "secret", "--history-dir", dir, "--filter", "available"})
+	}); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if _, err := captureStdout(t, func() error {
+		return RunProbe([]string{"--platform", "dashscope", "--api-key", "secret", "--history-dir", dir, "--out", "ndjson", "fail-model"})
+	}); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "history.json")
+	if err := RunHistory([]string{"--history-dir", dir, "--model", "fail-model", "--since", "1h", "--out", "json", "--output-file", outputPath}); err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	var entries []history.Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	// The scan's --filter only affects its output; history keeps every result.
+	if len(entries) != 2 || entries[0].Status != "fail" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if entries[0].KeyFingerprint == "" || strings.Contains(string(data), "secret") {
+		t.Fatalf("expected key fingerprint without the key, got %s", data)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return RunHistory([]string{"--history-dir", dir, "--filter", "status=ok"})
+	})
+	if err != nil {
+		t.Fatalf("history table: %v", err)
+	}
+	if !strings.Contains(out, "2 results from 1 scans") {
+		t.Fatalf("unexpected table output:\n%s", out)
+	}
+}
+
+func TestRunHistoryRequiresDirectory(t *testing.T) {
+	if err := RunHistory(nil); err == nil || !strings.Contains(err.Error(), "--history-dir") {
+		t.Fatalf("expected missing directory error, got %v", err)
+	}
+	if err := RunHistory([]string{"--history-dir", t.TempDir(), "--since", "yesterday"}); err == nil {
+		t.Fatalf("expected invalid --since error")
+	}
+}
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		raw      string
+		endOfDay bool
+		want     time.Time
+	}{
+		{raw: "", want: time.Time{}},
+		{raw: "90m", want: now.Add(-90 * time.Minute)},
+		{raw: "7d", want: now.AddDate(0, 0, -7)},
+		{raw: "2026-10-18T08:00:00Z", want: time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)},
+		{raw: "2026-10-18", want: time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)},
+		{raw: "2026-10-18", endOfDay: true, want: time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond)},
+	}
+	for _, tc := range cases {
+		got, err := parseTimeBound(tc.raw, now, tc.endOfDay)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.raw, err)
+		}
+		if !got.Equal(tc.want) {
+			t.Fatalf("parse %q: expected %v, got %v", tc.raw, tc.want, got)
+		}
+	}
+}
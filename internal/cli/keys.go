@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// apiKey is one key to probe with. The label defaults to the key's
+// fingerprint so the key itself never appears in results.
+type apiKey struct {
+	label string
+	key   string
+}
+
+// keyList collects repeated --api-key flags without ever printing them.
+type keyList []string
+
+func (k *keyList) String() string {
+	return fmt.Sprintf("%d keys", len(*k))
+}
+
+func (k *keyList) Set(value string) error {
+	*k = append(*k, value)
+	return nil
+}
+
+// keys resolves the keys to probe with: --api-key and --key-file if given,
+// otherwise the platform's keys in the config file, otherwise the first key
+// found in the platform's environment variables.
+func (o *engineOptions) keys(descriptor platform.Descriptor) ([]apiKey, error) {
+	var keys []apiKey
+	for _, key := range o.apiKeys {
+		keys = append(keys, apiKey{key: key})
+	}
+	if o.keyFile != "" {
+		fromFile, err := readKeyFile(o.keyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, fromFile...)
+	}
+	if len(keys) == 0 {
+		for _, entry := range o.config.Platform(descriptor.Name).Keys {
+			keys = append(keys, apiKey{label: entry.Label, key: os.ExpandEnv(entry.Key)})
+		}
+	}
+	if len(keys) == 0 {
+		key, _ := descriptor.EnvKey()
+		if key == "" {
+			return nil, fmt.Errorf("api key missing; provide --api-key or set %s", strings.Join(descriptor.KeyEnv, " or "))
+		}
+		keys = append(keys, apiKey{key: key})
+	}
+
+	labels := make(map[string]bool, len(keys))
+	fingerprints := make(map[string]bool, len(keys))
+	for i := range keys {
+		keys[i].key = strings.TrimSpace(keys[i].key)
+		if keys[i].key == "" {
+			return nil, fmt.Errorf("api key %d is empty", i+1)
+		}
+		fingerprint := platform.KeyFingerprint(keys[i].key)
+		if fingerprints[fingerprint] {
+			return nil, fmt.Errorf("api key %s is given twice", fingerprint)
+		}
+		fingerprints[fingerprint] = true
+		if keys[i].label == "" {
+			keys[i].label = fingerprint
+		}
+		if labels[keys[i].label] {
+			return nil, fmt.Errorf("duplicate key label: %s", keys[i].label)
+		}
+		labels[keys[i].label] = true
+	}
+	return keys, nil
+}
+
+// readKeyFile reads one key per line, optionally preceded by a label and
+// whitespace. Blank lines and lines starting with # are ignored.
+func readKeyFile(path string) ([]apiKey, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var keys []apiKey
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		switch fields := strings.Fields(text); len(fields) {
+		case 1:
+			keys = append(keys, apiKey{key: fields[0]})
+		case 2:
+			keys = append(keys, apiKey{label: fields[0], key: fields[1]})
+		default:
+			return nil, fmt.Errorf("%s:%d: expected a key or a label and a key", path, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
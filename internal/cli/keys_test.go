@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// keyedPlatform can only reach models when probed with the key "sk-full".
+type keyedPlatform struct {
+	key string
+}
+
+func (p keyedPlatform) Name() string {
+	return "fake"
+}
+
+func (p keyedPlatform) ListModels(_ context.Context) ([]platform.Model, error) {
+	return []platform.Model{{ID: "qwen-plus"}, {ID: "qwen-max"}}, nil
+}
+
+func (p keyedPlatform) Probe(_ context.Context, model platform.Model) platform.ProbeResult {
+	if p.key == "sk-full" || model.ID == "qwen-plus" {
+		return platform.ProbeResult{Platform: "fake", Model: model.ID, Status: "ok", Available: true, LatencyMS: 100}
+	}
+	return platform.ProbeResult{Platform: "fake", Model: model.ID, Status: "fail", Reason: "403 Forbidden"}
+}
+
+func useKeyedPlatform(t *testing.T) {
+	t.Helper()
+
+	prevFactory := platformFactory
+	platformFactory = func(_ string, opts platform.Options) (platform.Platform, error) {
+		return keyedPlatform{key: opts.APIKey}, nil
+	}
+	t.Cleanup(func() {
+		platformFactory = prevFactory
+	})
+}
+
+func TestKeys(t *testing.T) {
+	descriptor, err := platform.Lookup("dashscope")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(keyFile, []byte("# team keys\ncn sk-cn\n\nsk-unlabeled\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+	t.Setenv("DASHSCOPE_API_KEY", "sk-env")
+	t.Setenv("INTL_KEY", "sk-intl")
+	cfg := &config.Config{Platforms: map[string]config.PlatformConfig{
+		"dashscope": {Keys: []config.KeyConfig{{Label: "intl", Key: "${INTL_KEY}"}, {Key: "sk-other"}}},
+	}}
+
+	tests := []struct {
+		name string
+		opts engineOptions
+		want []apiKey
+	}{
+		{name: "environment", want: []apiKey{{platform.KeyFingerprint("sk-env"), "sk-env"}}},
+		{name: "config", opts: engineOptions{config: cfg}, want: []apiKey{{"intl", "sk-intl"}, {platform.KeyFingerprint("sk-other"), "sk-other"}}},
+		{
+			name: "flags and key file",
+			opts: engineOptions{apiKeys: keyList{"sk-flag"}, keyFile: keyFile, config: cfg},
+			want: []apiKey{
+				{platform.KeyFingerprint("sk-flag"), "sk-flag"},
+				{"cn", "sk-cn"},
+				{platform.KeyFingerprint("sk-unlabeled"), "sk-unlabeled"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := tt.opts.keys(descriptor)
+			if err != nil {
+				t.Fatalf("keys: %v", err)
+			}
+			if len(keys) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, keys)
+			}
+			for i := range keys {
+				if keys[i] != tt.want[i] {
+					t.Fatalf("key %d: expected %v, got %v", i, tt.want[i], keys[i])
+				}
+			}
+		})
+	}
+
+	for name, opts := range map[string]engineOptions{
+		"duplicate key":   {apiKeys: keyList{"sk-a", " sk-a"}},
+		"empty key":       {apiKeys: keyList{" "}},
+		"missing file":    {keyFile: filepath.Join(t.TempDir(), "missing")},
+		"duplicate label": {config: &config.Config{Platforms: map[string]config.PlatformConfig{"dashscope": {Keys: []config.KeyConfig{{Label: "a", Key: "sk-1"}, {Label: "a", Key: "sk-2"}}}}}},
+	} {
+		if _, err := opts.keys(descriptor); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRunProbeMultipleKeys(t *testing.T) {
+	useKeyedPlatform(t)
+
+	out, err := captureStdout(t, func() error {
+		return RunProbe([]string{"--platform", "dashscope", "--api-key", "sk-full", "--api-key", "sk-limited", "--out", "matrix", "qwen-plus", "qwen-max"})
+	})
+	if err != nil {
+		t.Fatalf("run probe: %v", err)
+	}
+	full, limited := platform.KeyFingerprint("sk-full"), platform.KeyFingerprint("sk-limited")
+	if strings.Contains(out, "sk-full") || strings.Contains(out, "sk-limited") {
+		t.Fatalf("keys must not be printed:\n%s", out)
+	}
+	for _, want := range []string{full, limited, "fail (forbidden)", "available per key: "} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out, err = captureStdout(t, func() error {
+		return RunProbe([]string{"--platform", "dashscope", "--api-key", "sk-full", "--api-key", "sk-limited", "--filter", "not available", "qwen-plus", "qwen-max"})
+	})
+	if err != nil {
+		t.Fatalf("run probe: %v", err)
+	}
+	if !strings.Contains(out, `"key": "`+limited+`"`) || strings.Contains(out, full) {
+		t.Fatalf("expected only the limited key's failure:\n%s", out)
+	}
+}
+
+func TestWatchRejectsMultipleKeys(t *testing.T) {
+	useKeyedPlatform(t)
+	opts := &engineOptions{platformName: "dashscope", apiKeys: keyList{"sk-a", "sk-b"}}
+	if _, err := opts.engine(); err == nil || !strings.Contains(err.Error(), "only scan and probe") {
+		t.Fatalf("expected several keys to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/output"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func (o *commonOptions) formatter() (func(io.Writer, []platform.ProbeResult) error, error) {
+	switch strings.ToLower(o.outFormat) {
+	case "json":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteJSON(w, results)
+		}, nil
+	case "yaml":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteYAML(w, results)
+		}, nil
+	case "table":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteTable(w, results, output.TableOptions{Color: useColor(w)})
+		}, nil
+	case "matrix":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteKeyMatrix(w, results)
+		}, nil
+	case "csv":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteCSV(w, results)
+		}, nil
+	case "tsv":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteTSV(w, results)
+		}, nil
+	case "markdown":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteMarkdown(w, results, output.ReportOptions{Generated: time.Now()})
+		}, nil
+	case "html":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteHTML(w, results, output.ReportOptions{Generated: time.Now()})
+		}, nil
+	case "junit":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteJUnit(w, results)
+		}, nil
+	case "prometheus":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WritePrometheus(w, results, output.ScanInfo{
+				Platform: strings.ToLower(o.platformName),
+				Started:  o.started,
+				Duration: time.Since(o.started),
+			})
+		}, nil
+	case "template":
+		if o.templateFile == "" {
+			return nil, errors.New("--out template requires --template")
+		}
+		text, err := os.ReadFile(o.templateFile)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := output.ParseTemplate(filepath.Base(o.templateFile), string(text))
+		if err != nil {
+			return nil, err
+		}
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			return output.WriteTemplate(w, tmpl, results)
+		}, nil
+	case "ndjson":
+		return func(w io.Writer, results []platform.ProbeResult) error {
+			for _, result := range results {
+				if err := output.WriteNDJSON(w, result); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", o.outFormat)
+	}
+}
+
+func validateSort(by string) error {
+	switch strings.ToLower(by) {
+	case "", "model", "status", "latency":
+		return nil
+	default:
+		return fmt.Errorf("unsupported sort key: %s (expected model, status or latency)", by)
+	}
+}
+
+// sortResults orders results by the given key, breaking ties by platform and
+// model so output is stable across runs.
+func sortResults(results []platform.ProbeResult, by string) {
+	byName := func(a, b platform.ProbeResult) int {
+		return cmp.Or(cmp.Compare(a.Platform, b.Platform), cmp.Compare(a.Model, b.Model))
+	}
+	switch strings.ToLower(by) {
+	case "model":
+		slices.SortStableFunc(results, byName)
+	case "status":
+		slices.SortStableFunc(results, func(a, b platform.ProbeResult) int {
+			return cmp.Or(cmp.Compare(a.Status, b.Status), byName(a, b))
+		})
+	case "latency":
+		slices.SortStableFunc(results, func(a, b platform.ProbeResult) int {
+			return cmp.Or(cmp.Compare(a.LatencyMS, b.LatencyMS), byName(a, b))
+		})
+	}
+}
+
+func useColor(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func writeOutput(outputFile string, write func(io.Writer) error) error {
+	if outputFile == "" {
+		return write(os.Stdout)
+	}
+	writer, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	if err := write(writer); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// atomicOutput replaces outputFile through a temporary file in the same
+// directory, so readers such as node_exporter's textfile collector never see
+// a partial file. Targets that exist but are not regular files, such as
+// /dev/stdout, FIFOs or symlinks, are written in place with writeOutput.
+func atomicOutput(outputFile string, write func(io.Writer) error) error {
+	if outputFile == "" {
+		return write(os.Stdout)
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Lstat(outputFile); err == nil {
+		if !info.Mode().IsRegular() {
+			return writeOutput(outputFile, write)
+		}
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(outputFile), "."+filepath.Base(outputFile)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), outputFile)
+}
+
+// appendOutput is like writeOutput but appends to an existing file, for
+// long-running commands whose output should survive a restart.
+func appendOutput(outputFile string, write func(io.Writer) error) error {
+	if outputFile == "" {
+		return write(os.Stdout)
+	}
+	writer, err := os.OpenFile(outputFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := write(writer); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
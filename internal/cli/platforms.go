@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/NERVEbing/model-scout/internal/output"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+type platformInfo struct {
+	Name       string   `json:"name" yaml:"name"`
+	Aliases    []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	BaseURL    string   `json:"base_url" yaml:"base_url"`
+	Auth       string   `json:"auth" yaml:"auth"`
+	ProbeKinds []string `json:"probe_kinds" yaml:"probe_kinds"`
+	KeyEnv     []string `json:"key_env" yaml:"key_env"`
+	// CredentialEnv is the variable a key was found in; empty when none of
+	// KeyEnv is set.
+	CredentialEnv  string `json:"credential_env,omitempty" yaml:"credential_env,omitempty"`
+	KeyFingerprint string `json:"key_fingerprint,omitempty" yaml:"key_fingerprint,omitempty"`
+}
+
+// RunPlatforms lists the registered platforms and whether a key for each is
+// set in the environment.
+func RunPlatforms(args []string) error {
+	flags := flag.NewFlagSet("platforms", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	outFormat := flags.String("out", "table", "output format: table, json or yaml")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	var infos []platformInfo
+	for _, d := range platform.Descriptors() {
+		info := platformInfo{
+			Name:       d.Name,
+			Aliases:    d.Aliases,
+			BaseURL:    d.BaseURL,
+			Auth:       d.Auth,
+			ProbeKinds: d.ProbeKinds,
+			KeyEnv:     d.KeyEnv,
+		}
+		if key, env := d.EnvKey(); key != "" {
+			info.CredentialEnv = env
+			info.KeyFingerprint = platform.KeyFingerprint(key)
+		}
+		infos = append(infos, info)
+	}
+
+	switch strings.ToLower(*outFormat) {
+	case "table":
+		return writePlatformsTable(os.Stdout, infos)
+	case "json":
+		return output.WriteJSON(os.Stdout, infos)
+	case "yaml":
+		return output.WriteYAML(os.Stdout, infos)
+	default:
+		return fmt.Errorf("unsupported output format: %s", *outFormat)
+	}
+}
+
+func writePlatformsTable(w io.Writer, infos []platformInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLATFORM\tALIASES\tBASE URL\tAUTH\tPROBES\tKEY ENV\tCREDENTIALS")
+	for _, info := range infos {
+		credentials := "missing"
+		if info.CredentialEnv != "" {
+			credentials = "found in " + info.CredentialEnv
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Name,
+			orDash(strings.Join(info.Aliases, ",")),
+			orDash(info.BaseURL),
+			orDash(info.Auth),
+			orDash(strings.Join(info.ProbeKinds, ",")),
+			orDash(strings.Join(info.KeyEnv, ",")),
+			credentials,
+		)
+	}
+	return tw.Flush()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunPlatforms(t *testing.T) {
+	t.Setenv("DASHSCOPE_API_KEY", "sk-dashscope")
+	t.Setenv("DEEPSEEK_API_KEY", "")
+
+	out, err := captureStdout(t, func() error {
+		return RunPlatforms(nil)
+	})
+	if err != nil {
+		t.Fatalf("run platforms: %v", err)
+	}
+	for _, want := range []string{"PLATFORM", "dashscope", "bailian", "DASHSCOPE_API_KEY", "https://dashscope.aliyuncs.com", "bearer", "chat", "found in DASHSCOPE_API_KEY", "deepseek", "DEEPSEEK_API_KEY", "missing"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out, err = captureStdout(t, func() error {
+		return RunPlatforms([]string{"--out", "json"})
+	})
+	if err != nil {
+		t.Fatalf("run platforms: %v", err)
+	}
+	var infos []platformInfo
+	if err := json.Unmarshal([]byte(out), &infos); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(infos) < 2 || infos[0].Name != "dashscope" || infos[1].Name != "deepseek" {
+		t.Fatalf("unexpected platforms: %+v", infos)
+	}
+	if infos[0].CredentialEnv != "DASHSCOPE_API_KEY" || infos[0].KeyFingerprint == "" || infos[1].CredentialEnv != "" {
+		t.Fatalf("unexpected credentials: %+v", infos)
+	}
+	if strings.Contains(out, "sk-dashscope") {
+		t.Fatal("the key itself must not be printed")
+	}
+
+	if err := RunPlatforms([]string{"--out", "csv"}); err == nil {
+		t.Fatal("expected an unsupported format to fail")
+	}
+}
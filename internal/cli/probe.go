@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func RunProbe(args []string) error {
+	flags := flag.NewFlagSet("probe", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	opts := registerCommonFlags(flags)
+	modelsFile := flags.String("models-file", "", "file with model IDs, one per line (- for stdin)")
+
+	ids, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if *modelsFile != "" {
+		fromFile, err := readModelsFile(*modelsFile)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, fromFile...)
+	}
+	models := uniqueModels(ids)
+	if len(models) == 0 {
+		return errors.New("no models to probe; pass model IDs as arguments or use --models-file")
+	}
+
+	engine, err := opts.engine()
+	if err != nil {
+		return err
+	}
+
+	return opts.probe(context.Background(), engine, models)
+}
+
+// parseInterspersed parses flags that may appear before, between or after
+// positional arguments and returns the positional arguments in order.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flags.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func readModelsFile(path string) ([]string, error) {
+	var reader io.Reader
+	if path == "-" {
+		reader = os.Stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+	return readModelIDs(reader)
+}
+
+func readModelIDs(r io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func uniqueModels(ids []string) []platform.Model {
+	seen := make(map[string]bool, len(ids))
+	models := make([]platform.Model, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		models = append(models, platform.Model{ID: id})
+	}
+	return models
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestRunProbeExplicitModels(t *testing.T) {
+	prevFactory := platformFactory
+	platformFactory = func(string, platform.Options) (platform.Platform, error) {
+		return &fakePlatform{}, nil
+	}
+	t.Cleanup(func() {
+		platformFactory = prevFactory
+	})
+
+	dir := t.TempDir()
+	modelsPath := filepath.Join(dir, "models.txt")
+	if err := os.WriteFile(modelsPath, []byte("# snapshots\nfail-model\n\nok-model\n"), 0o644); err != nil {
+		t.Fatalf("write models file: %v", err)
+	}
+
+	outputPath := filepath.Join(dir, "out.json")
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+	args := []string{
+		"ok-model",
+		"--platform", "deepseek",
+		"--output-file", outputPath,
+		"--models-file", modelsPath,
+		"image-model",
+	}
+	if err := RunProbe(args); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+
+	var results []platform.ProbeResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+
+	got := make(map[string]string, len(results))
+	for _, result := range results {
+		got[result.Model] = result.Status
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %#v", got)
+	}
+	if got["ok-model"] != "ok" || got["fail-model"] != "fail" || got["image-model"] != "fail" {
+		t.Fatalf("unexpected results: %#v", got)
+	}
+}
+
+func TestRunProbeRequiresModels(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+	err := RunProbe([]string{"--platform", "deepseek"})
+	if err == nil || !strings.Contains(err.Error(), "no models") {
+		t.Fatalf("expected missing models error, got %v", err)
+	}
+}
+
+func TestParseInterspersed(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	name := flags.String("platform", "", "")
+
+	positional, err := parseInterspersed(flags, []string{"a", "--platform", "x", "b", "--", "--c"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if *name != "x" {
+		t.Fatalf("expected platform x, got %q", *name)
+	}
+	if strings.Join(positional, " ") != "a b --c" {
+		t.Fatalf("unexpected positional args: %#v", positional)
+	}
+}
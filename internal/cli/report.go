@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/filter"
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/output"
+)
+
+func RunReport(args []string) error {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	dir := flags.String("history-dir", "", "history directory")
+	configFile := flags.String("config", "", "config file path (YAML)")
+	platformName := flags.String("platform", "", "only report on this platform")
+	model := flags.String("model", "", "only report on this model ID")
+	key := flags.String("key", "", "only report on results of this key label or fingerprint")
+	since := flags.String("since", "7d", "start of the window: a duration such as 24h or 7d, a date or an RFC 3339 time")
+	until := flags.String("until", "", "end of the window (same forms as --since; default now)")
+	flapThreshold := flags.Int("flap-threshold", history.DefaultFlapThreshold, "ok/fail transitions within the window that mark a model as flapping")
+	outFormat := flags.String("out", "table", "output format: table, json, yaml or markdown")
+	outputFile := flags.String("output-file", "", "output file path")
+	var filters filterExpressions
+	flags.Var(&filters, "filter", "only include results matching an expression (repeatable, combined with and)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *flapThreshold <= 0 {
+		return fmt.Errorf("--flap-threshold must be positive")
+	}
+	store, err := openHistory(*dir, *configFile)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	query, err := historyQuery(*platformName, *model, *key, *since, *until, now)
+	if err != nil {
+		return err
+	}
+	expr, err := parseFilters(filters)
+	if err != nil {
+		return err
+	}
+	write, err := reportFormatter(*outFormat)
+	if err != nil {
+		return err
+	}
+
+	scans, err := store.Query(query)
+	if err != nil {
+		return err
+	}
+	summary := history.Summarize(filterScans(scans, expr), history.SummaryOptions{FlapThreshold: *flapThreshold})
+	if !query.Since.IsZero() {
+		summary.Since = query.Since
+		summary.Until = now
+	}
+	if !query.Until.IsZero() {
+		summary.Until = query.Until
+	}
+	return writeOutput(*outputFile, func(w io.Writer) error {
+		return write(w, summary)
+	})
+}
+
+// filterScans keeps the results matching expr. Scans stay in place even when
+// all their results are filtered out so the scan count covers the window.
+func filterScans(scans []history.Scan, expr filter.Expr) []history.Scan {
+	if expr == nil {
+		return scans
+	}
+	for i := range scans {
+		scans[i].Results = filter.Apply(scans[i].Results, expr)
+	}
+	return scans
+}
+
+func reportFormatter(format string) (func(io.Writer, history.Summary) error, error) {
+	switch strings.ToLower(format) {
+	case "table":
+		return output.WriteAvailabilityTable, nil
+	case "markdown":
+		return output.WriteAvailabilityMarkdown, nil
+	case "json":
+		return func(w io.Writer, summary history.Summary) error {
+			return output.WriteJSON(w, summary)
+		}, nil
+	case "yaml":
+		return func(w io.Writer, summary history.Summary) error {
+			return output.WriteYAML(w, summary)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestRunReport(t *testing.T) {
+	dir := t.TempDir()
+	store, err := history.Open(dir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	now := time.Now()
+	for i, available := range []bool{true, false, true, false, true} {
+		status := "fail"
+		if available {
+			status = "ok"
+		}
+		_, err := store.Append(history.Scan{
+			Time:     now.Add(time.Duration(i-5) * time.Hour),
+			Platform: "dashscope",
+			Results: []platform.ProbeResult{
+				{Platform: "dashscope", Model: "qwen-plus", Status: status, Available: available},
+				{Platform: "dashscope", Model: "qwen-max", Status: "ok", Available: true, LatencyMS: 500},
+			},
+		})
+		if err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	// Outside the default 7 day window.
+	if _, err := store.Append(history.Scan{Time: now.AddDate(0, 0, -8), Platform: "dashscope", Results: []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-max", Status: "fail"},
+	}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.json")
+	if err := RunReport([]string{"--history-dir", dir, "--out", "json", "--output-file", outputPath}); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	var summary history.Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if summary.Scans != 5 || len(summary.Models) != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	qwenMax, qwenPlus := summary.Models[0], summary.Models[1]
+	if qwenMax.Availability != 100 || qwenMax.MeanLatencyMS != 500 || qwenMax.Flapping {
+		t.Fatalf("unexpected qwen-max stats: %+v", qwenMax)
+	}
+	if qwenPlus.Availability != 60 || !qwenPlus.Flapping || qwenPlus.LongestOutageMS != time.Hour.Milliseconds() {
+		t.Fatalf("unexpected qwen-plus stats: %+v", qwenPlus)
+	}
+
+	if err := RunReport([]string{"--history-dir", dir, "--since", "30d", "--filter", "model = qwen-max", "--out", "json", "--output-file", outputPath}); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	data, err = os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	summary = history.Summary{}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if summary.Scans != 6 || len(summary.Models) != 1 || summary.Models[0].Samples != 6 {
+		t.Fatalf("unexpected filtered summary: %+v", summary)
+	}
+}
+
+func TestRunReportRejectsInvalidOptions(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"--history-dir", dir, "--out", "csv"},
+		{"--history-dir", dir, "--flap-threshold", "0"},
+		{"--history-dir", dir, "--since", "1d", "--until", "2d"},
+	} {
+		if err := RunReport(args); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
@@ -5,79 +5,318 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/filter"
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/notify"
 	"github.com/NERVEbing/model-scout/internal/output"
 	"github.com/NERVEbing/model-scout/internal/platform"
-	"github.com/NERVEbing/model-scout/internal/platform/dashscope"
-	"github.com/NERVEbing/model-scout/internal/platform/deepseek"
+	_ "github.com/NERVEbing/model-scout/internal/platform/all"
 	"github.com/NERVEbing/model-scout/internal/scout"
 )
 
-const defaultDashscopeKeyEnv = "DASHSCOPE_API_KEY"
-
 var platformFactory = platformFromName
 
-func Run(args []string) error {
-	flags := flag.NewFlagSet("scan", flag.ContinueOnError)
-	flags.SetOutput(os.Stderr)
-	platformName := flags.String("platform", "", "platform to scan")
-	apiKey := flags.String("api-key", "", "api key")
-	workers := flags.Int("workers", 4, "number of workers")
-	timeout := flags.Duration("timeout", 15*time.Second, "http timeout")
-	outFormat := flags.String("out", "json", "output format: json or yaml")
-	outputFile := flags.String("output-file", "", "output file path")
-	exclude := flags.String("exclude", "", "comma-separated substrings to exclude")
-	var filters filterExpressions
-	flags.Var(&filters, "filter", "filter output: key=value or key!=value (keys: available,status,model,platform)")
+// engineOptions are the flags needed to build an engine for a platform.
+type engineOptions struct {
+	platformName string
+	apiKeys      keyList
+	keyFile      string
+	transport    transportFlags
+	workers      int
+	timeout      time.Duration
+	configFile   string
+	historyDir   string
 
-	if err := flags.Parse(args); err != nil {
-		return err
+	config         *config.Config
+	keyFingerprint string
+	started        time.Time
+	// multipleKeys allows probing with several keys at once.
+	multipleKeys bool
+}
+
+// commonOptions adds the output flags shared by scan and probe.
+type commonOptions struct {
+	engineOptions
+	outFormat    string
+	outputFile   string
+	sortBy       string
+	templateFile string
+	filters      filterExpressions
+
+	expr filter.Expr
+}
+
+func registerEngineFlags(flags *flag.FlagSet, opts *engineOptions) {
+	flags.StringVar(&opts.platformName, "platform", "", "platform to scan: "+strings.Join(platform.Names(), ", "))
+	flags.Var(&opts.apiKeys, "api-key", "api key (repeatable to compare keys)")
+	flags.StringVar(&opts.keyFile, "key-file", "", "file with api keys, one per line as 'key' or 'label key'")
+	flags.IntVar(&opts.workers, "workers", 4, "number of workers")
+	flags.DurationVar(&opts.timeout, "timeout", 15*time.Second, "http timeout")
+	flags.StringVar(&opts.configFile, "config", "", "config file path (YAML)")
+	flags.StringVar(&opts.historyDir, "history-dir", "", "record results in this history directory")
+	registerTransportFlags(flags, &opts.transport)
+}
+
+func registerCommonFlags(flags *flag.FlagSet) *commonOptions {
+	opts := &commonOptions{engineOptions: engineOptions{multipleKeys: true}}
+	registerEngineFlags(flags, &opts.engineOptions)
+	flags.StringVar(&opts.outFormat, "out", "json", "output format: json, yaml, ndjson, table, matrix, csv, tsv, markdown, html, junit, prometheus or template")
+	flags.StringVar(&opts.outputFile, "output-file", "", "output file path")
+	flags.StringVar(&opts.templateFile, "template", "", "Go text/template file used by --out template")
+	flags.StringVar(&opts.sortBy, "sort", "", "sort results by model, status or latency")
+	flags.Var(&opts.filters, "filter", "filter output with an expression, e.g. 'available and latency < 2s' (repeatable, combined with and)")
+	return opts
+}
+
+// engine validates the output flags before building the engine, so mistakes
+// are reported before any request is made.
+func (o *commonOptions) engine() (scout.Engine, error) {
+	if o.platformName == "" {
+		return scout.Engine{}, errors.New("--platform is required")
+	}
+	expr, err := parseFilters(o.filters)
+	if err != nil {
+		return scout.Engine{}, err
+	}
+	o.expr = expr
+	if _, err := o.formatter(); err != nil {
+		return scout.Engine{}, err
+	}
+	if o.templateFile != "" && !strings.EqualFold(o.outFormat, "template") {
+		return scout.Engine{}, errors.New("--template requires --out template")
+	}
+	if err := validateSort(o.sortBy); err != nil {
+		return scout.Engine{}, err
+	}
+	if o.sortBy != "" && o.streaming() {
+		return scout.Engine{}, errors.New("--sort is not supported with --out ndjson")
+	}
+	return o.engineOptions.engine()
+}
+
+func (o *engineOptions) engine() (scout.Engine, error) {
+	if o.platformName == "" {
+		return scout.Engine{}, errors.New("--platform is required")
+	}
+	if o.configFile != "" {
+		cfg, err := config.Load(o.configFile)
+		if err != nil {
+			return scout.Engine{}, err
+		}
+		o.config = cfg
+		if o.historyDir == "" {
+			o.historyDir = cfg.HistoryDir
+		}
 	}
 
-	if *platformName == "" {
-		return errors.New("--platform is required")
+	descriptor, err := platform.Lookup(o.platformName)
+	if err != nil {
+		return scout.Engine{}, err
+	}
+	o.platformName = descriptor.Name
+	keys, err := o.keys(descriptor)
+	if err != nil {
+		return scout.Engine{}, err
+	}
+	if len(keys) > 1 && !o.multipleKeys {
+		return scout.Engine{}, fmt.Errorf("%d api keys given for %s; only scan and probe compare several keys", len(keys), o.platformName)
+	}
+	httpClient, err := o.httpClient()
+	if err != nil {
+		return scout.Engine{}, err
 	}
+	multiKeys := make([]scout.Key, 0, len(keys))
+	for _, key := range keys {
+		platformImpl, err := platformFactory(o.platformName, platform.Options{APIKey: key.key, Timeout: o.timeout, HTTPClient: httpClient})
+		if err != nil {
+			return scout.Engine{}, err
+		}
+		multiKeys = append(multiKeys, scout.Key{Label: key.label, Platform: platformImpl})
+	}
+	platformImpl := multiKeys[0].Platform
+	o.keyFingerprint = platform.KeyFingerprint(keys[0].key)
+	if len(multiKeys) > 1 {
+		if platformImpl, err = scout.NewMultiKey(multiKeys); err != nil {
+			return scout.Engine{}, err
+		}
+		o.keyFingerprint = ""
+	}
+	o.started = time.Now()
+	return scout.Engine{Platform: platformImpl, Workers: o.workers}, nil
+}
 
-	key := strings.TrimSpace(*apiKey)
-	if key == "" {
-		keyEnvName, err := defaultKeyEnv(*platformName)
+// notifiers builds the notifiers from the config file, if any.
+func (o *engineOptions) notifiers() ([]*notify.Notifier, error) {
+	if o.config == nil {
+		return nil, nil
+	}
+	return notify.NewAll(o.config.Notifiers)
+}
+
+func (o *engineOptions) defaultExcludes(platformImpl platform.Platform) []string {
+	if override := o.config.Platform(o.platformName).DefaultExcludes; override != nil {
+		return *override
+	}
+	return platform.DefaultExcludes(platformImpl)
+}
+
+func (o *commonOptions) streaming() bool {
+	return strings.EqualFold(o.outFormat, "ndjson")
+}
+
+// probe runs the engine over models and writes the filtered results. NDJSON
+// output is written line by line as probes complete; other formats are
+// written once every probe has finished. With a history directory, every
+// result is recorded, regardless of --filter.
+func (o *commonOptions) probe(ctx context.Context, engine scout.Engine, models []platform.Model) error {
+	if o.streaming() {
+		var results []platform.ProbeResult
+		err := writeOutput(o.outputFile, func(w io.Writer) error {
+			return engine.Stream(ctx, models, func(result platform.ProbeResult) error {
+				results = append(results, result)
+				if o.expr != nil && !o.expr.Match(result) {
+					return nil
+				}
+				return output.WriteNDJSON(w, result)
+			})
+		})
 		if err != nil {
 			return err
 		}
-		key = strings.TrimSpace(os.Getenv(keyEnvName))
-		if key == "" {
-			return fmt.Errorf("api key missing; provide --api-key or set %s", keyEnvName)
-		}
+		return o.record(engine.Platform.Name(), o.started, results)
 	}
 
-	platformImpl, err := platformFactory(*platformName, key, *timeout)
+	results, err := engine.ProbeModels(ctx, models)
 	if err != nil {
 		return err
 	}
+	if err := o.record(engine.Platform.Name(), o.started, results); err != nil {
+		return err
+	}
+	return o.write(results)
+}
 
-	engine := scout.Engine{Platform: platformImpl, Workers: *workers}
-	ctx := context.Background()
-	excludes := splitExclude(*exclude)
-	results, err := engine.Scan(ctx, excludes)
+func (o *engineOptions) record(platformName string, started time.Time, results []platform.ProbeResult) error {
+	if o.historyDir == "" {
+		return nil
+	}
+	store, err := history.Open(o.historyDir)
+	if err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	_, err = store.Append(history.Scan{
+		Time:           started,
+		Platform:       strings.ToLower(platformName),
+		KeyFingerprint: o.keyFingerprint,
+		Results:        results,
+	})
+	if err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	return nil
+}
+
+func (o *commonOptions) write(results []platform.ProbeResult) error {
+	format, err := o.formatter()
 	if err != nil {
 		return err
 	}
+	results = applyFilters(results, o.expr)
+	sortResults(results, o.sortBy)
+	write := writeOutput
+	if strings.EqualFold(o.outFormat, "prometheus") {
+		write = atomicOutput
+	}
+	return write(o.outputFile, func(w io.Writer) error {
+		return format(w, results)
+	})
+}
+
+type selectionOptions struct {
+	includes          patternList
+	excludes          patternList
+	noDefaultExcludes bool
+}
 
-	parsedFilters, err := parseFilters(filters)
+func registerSelectionFlags(flags *flag.FlagSet) *selectionOptions {
+	opts := &selectionOptions{}
+	flags.Var(&opts.includes, "include", "only probe models matching these patterns (repeatable, comma-separated; glob or re:regex)")
+	flags.Var(&opts.excludes, "exclude", "skip models matching these patterns (repeatable, comma-separated; glob or re:regex)")
+	flags.BoolVar(&opts.noDefaultExcludes, "no-default-excludes", false, "do not apply the platform's default exclusion patterns")
+	return opts
+}
+
+func (s *selectionOptions) selector(opts *engineOptions, platformImpl platform.Platform) (scout.Selector, error) {
+	var defaults []string
+	if !s.noDefaultExcludes {
+		defaults = opts.defaultExcludes(platformImpl)
+	}
+	return scout.NewSelector(s.includes, s.excludes, defaults)
+}
+
+func Run(args []string) error {
+	flags := flag.NewFlagSet("scan", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	opts := registerCommonFlags(flags)
+	selection := registerSelectionFlags(flags)
+	dryRun := flags.Bool("dry-run", false, "list models and selection decisions without probing")
+	verbose := flags.Bool("verbose", false, "print selection decisions to stderr")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	engine, err := opts.engine()
 	if err != nil {
 		return err
 	}
-	if len(parsedFilters) > 0 {
-		results = applyFilters(results, parsedFilters)
+	selector, err := selection.selector(&opts.engineOptions, engine.Platform)
+	if err != nil {
+		return err
 	}
 
-	return writeOutput(*outFormat, *outputFile, results)
+	ctx := context.Background()
+	selected, decisions, err := engine.Select(ctx, selector)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		return writeDecisions(os.Stdout, decisions)
+	}
+	if *verbose {
+		if err := writeDecisions(os.Stderr, decisions); err != nil {
+			return err
+		}
+	}
+
+	return opts.probe(ctx, engine, selected)
+}
+
+type patternList []string
+
+func (p *patternList) String() string {
+	return strings.Join(*p, ",")
+}
+
+// Set splits comma-separated values, except for regular expressions which are
+// kept whole because commas are meaningful inside them (e.g. "re:a{1,3}").
+func (p *patternList) Set(value string) error {
+	if strings.HasPrefix(strings.TrimSpace(value), "re:") {
+		*p = append(*p, strings.TrimSpace(value))
+		return nil
+	}
+	*p = append(*p, splitList(value)...)
+	return nil
 }
 
-func splitExclude(raw string) []string {
+func splitList(raw string) []string {
 	if raw == "" {
 		return nil
 	}
@@ -93,48 +332,27 @@ func splitExclude(raw string) []string {
 	return filtered
 }
 
-func defaultKeyEnv(platformName string) (string, error) {
-	switch strings.ToLower(platformName) {
-	case "dashscope":
-		return defaultDashscopeKeyEnv, nil
-	case "deepseek":
-		return "DEEPSEEK_API_KEY", nil
-	default:
-		return "", fmt.Errorf("unsupported platform: %s", platformName)
-	}
-}
-
-func platformFromName(name, apiKey string, timeout time.Duration) (platform.Platform, error) {
-	switch strings.ToLower(name) {
-	case "dashscope":
-		return dashscope.NewPlatform(apiKey, timeout), nil
-	case "deepseek":
-		return deepseek.NewPlatform(apiKey, timeout), nil
-	default:
-		return nil, fmt.Errorf("unsupported platform: %s", name)
-	}
-}
-
-func writeOutput(format, outputFile string, payload []platform.ProbeResult) error {
-	format = strings.ToLower(format)
-	var err error
-	var writer *os.File
-	if outputFile == "" {
-		writer = os.Stdout
-	} else {
-		writer, err = os.Create(outputFile)
-		if err != nil {
-			return err
+func writeDecisions(w io.Writer, decisions []scout.Decision) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tMODEL\tRULE\tPATTERN")
+	for _, decision := range decisions {
+		action := "skip"
+		if decision.Selected {
+			action = "probe"
 		}
-		defer writer.Close()
+		pattern := decision.Pattern
+		if pattern == "" {
+			pattern = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", action, decision.Model, decision.Rule, pattern)
 	}
+	return tw.Flush()
+}
 
-	switch format {
-	case "json":
-		return output.WriteJSON(writer, payload)
-	case "yaml":
-		return output.WriteYAML(writer, payload)
-	default:
-		return fmt.Errorf("unsupported output format: %s", format)
+func platformFromName(name string, opts platform.Options) (platform.Platform, error) {
+	descriptor, err := platform.Lookup(name)
+	if err != nil {
+		return nil, err
 	}
+	return descriptor.New(opts)
 }
@@ -3,10 +3,11 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/NERVEbing/model-scout/internal/platform"
 )
@@ -49,7 +50,7 @@ func TestRunOutputsFilteredResults(t *testing.T) {
 	t.Helper()
 
 	prevFactory := platformFactory
-	platformFactory = func(_ string, _ string, _ time.Duration) (platform.Platform, error) {
+	platformFactory = func(string, platform.Options) (platform.Platform, error) {
 		return &fakePlatform{}, nil
 	}
 	t.Cleanup(func() {
@@ -89,3 +90,266 @@ func TestRunOutputsFilteredResults(t *testing.T) {
 		t.Fatalf("unexpected platform: %s", results[0].Platform)
 	}
 }
+
+func useFakePlatform(t *testing.T) {
+	t.Helper()
+
+	prevFactory := platformFactory
+	platformFactory = func(string, platform.Options) (platform.Platform, error) {
+		return &fakePlatform{}, nil
+	}
+	t.Cleanup(func() {
+		platformFactory = prevFactory
+	})
+}
+
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	fnErr := fn()
+	writer.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read stdout: %v", err)
+	}
+	return string(data), fnErr
+}
+
+func TestRunDryRunListsDecisions(t *testing.T) {
+	useFakePlatform(t)
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+	args := []string{
+		"--platform", "deepseek",
+		"--include", "*-model",
+		"--exclude", "re:^skip",
+		"--dry-run",
+	}
+	out, err := captureStdout(t, func() error { return Run(args) })
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 decisions, got %q", out)
+	}
+	if fields := strings.Fields(lines[2]); len(fields) != 4 || fields[0] != "skip" || fields[1] != "skip-model" || fields[3] != "re:^skip" {
+		t.Fatalf("unexpected decision line: %q", lines[2])
+	}
+}
+
+func TestRunConfigDefaultExcludes(t *testing.T) {
+	useFakePlatform(t)
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte("platforms:\n  deepseek:\n    default_excludes: [fail]\n")
+	if err := os.WriteFile(configPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return Run([]string{"--platform", "deepseek", "--config", configPath, "--dry-run"})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(out, "fail-model  default-exclude  fail") {
+		t.Fatalf("expected config default exclude, got %q", out)
+	}
+
+	out, err = captureStdout(t, func() error {
+		return Run([]string{"--platform", "deepseek", "--config", configPath, "--no-default-excludes", "--dry-run"})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(out, "default-exclude") {
+		t.Fatalf("expected no default excludes, got %q", out)
+	}
+}
+
+func TestRunNDJSONOutput(t *testing.T) {
+	useFakePlatform(t)
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+
+	out, err := captureStdout(t, func() error {
+		return Run([]string{"--platform", "deepseek", "--out", "ndjson", "--filter", "available"})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", out)
+	}
+	for _, line := range lines {
+		var result platform.ProbeResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("unmarshal line %q: %v", line, err)
+		}
+		if !result.Available {
+			t.Fatalf("expected available result, got %#v", result)
+		}
+	}
+}
+
+func TestRunRejectsInvalidOptionsBeforeScanning(t *testing.T) {
+	prevFactory := platformFactory
+	platformFactory = func(string, platform.Options) (platform.Platform, error) {
+		t.Fatalf("platform should not be created")
+		return nil, nil
+	}
+	t.Cleanup(func() {
+		platformFactory = prevFactory
+	})
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+
+	if err := Run([]string{"--platform", "deepseek", "--out", "xml"}); err == nil {
+		t.Fatalf("expected unsupported format error")
+	}
+	if err := Run([]string{"--platform", "deepseek", "--filter", "latency <"}); err == nil {
+		t.Fatalf("expected filter error")
+	}
+}
+
+func TestRunTableSortedByStatus(t *testing.T) {
+	useFakePlatform(t)
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+
+	out, err := captureStdout(t, func() error {
+		return Run([]string{"--platform", "deepseek", "--out", "table", "--sort", "status"})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header, 3 rows and footer, got %q", out)
+	}
+	var models []string
+	for _, line := range lines[1:4] {
+		models = append(models, strings.Fields(line)[1])
+	}
+	if strings.Join(models, ",") != "fail-model,ok-model,skip-model" {
+		t.Fatalf("unexpected order: %v", models)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Fatalf("expected no color when stdout is not a terminal")
+	}
+}
+
+func TestRunRejectsSortWithNDJSON(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+	if err := Run([]string{"--platform", "deepseek", "--out", "ndjson", "--sort", "model"}); err == nil {
+		t.Fatalf("expected error for --sort with ndjson")
+	}
+	if err := Run([]string{"--platform", "deepseek", "--sort", "size"}); err == nil {
+		t.Fatalf("expected error for unsupported sort key")
+	}
+}
+
+func TestRunTemplateOutput(t *testing.T) {
+	useFakePlatform(t)
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+	templatePath := filepath.Join(t.TempDir(), "env.tmpl")
+	text := `{{range .}}{{if .Available}}{{upper .Model}}=1
+{{end}}{{end}}`
+	if err := os.WriteFile(templatePath, []byte(text), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return Run([]string{"--platform", "deepseek", "--out", "template", "--template", templatePath, "--sort", "model"})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out != "OK-MODEL=1\nSKIP-MODEL=1\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	if err := Run([]string{"--platform", "deepseek", "--out", "template"}); err == nil {
+		t.Fatalf("expected error without --template")
+	}
+}
+
+func TestRunPrometheusOutputFile(t *testing.T) {
+	useFakePlatform(t)
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "model_scout.prom")
+
+	if err := Run([]string{"--platform", "deepseek", "--out", "prometheus", "--output-file", outputPath}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	for _, want := range []string{
+		`model_scout_model_available{platform="fake",model="ok-model"} 1`,
+		`model_scout_model_available{platform="fake",model="fail-model"} 0`,
+		`model_scout_scan_duration_seconds{platform="deepseek"} `,
+		`model_scout_scan_timestamp_seconds{platform="deepseek"} `,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, data)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected temporary files to be cleaned up, got %v", entries)
+	}
+}
+
+func TestOutputFileKeepsTarget(t *testing.T) {
+	useFakePlatform(t)
+	t.Setenv("DEEPSEEK_API_KEY", "token")
+	dir := t.TempDir()
+	target := filepath.Join(dir, "results.prom")
+	if err := os.WriteFile(target, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.prom")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{"json", "prometheus"} {
+		if err := Run([]string{"--platform", "deepseek", "--out", format, "--output-file", link}); err != nil {
+			t.Fatalf("%s: expected no error, got %v", format, err)
+		}
+		info, err := os.Lstat(link)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			t.Fatalf("%s: expected the symlink to be kept, got %v, %v", format, info, err)
+		}
+		info, err = os.Stat(target)
+		if err != nil || info.Mode().Perm() != 0o600 || info.Size() == 0 {
+			t.Fatalf("%s: expected the target to be written with its mode kept, got %v, %v", format, info, err)
+		}
+
+		if err := Run([]string{"--platform", "deepseek", "--out", format, "--output-file", target}); err != nil {
+			t.Fatalf("%s: expected no error, got %v", format, err)
+		}
+		if info, err := os.Stat(target); err != nil || info.Mode().Perm() != 0o600 {
+			t.Fatalf("%s: expected mode 0600 to be kept, got %v, %v", format, info, err)
+		}
+	}
+}
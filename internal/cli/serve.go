@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/platform"
+	"github.com/NERVEbing/model-scout/internal/server"
+)
+
+func RunServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	opts := &engineOptions{}
+	registerEngineFlags(flags, opts)
+	selection := registerSelectionFlags(flags)
+	listen := flags.String("listen", "127.0.0.1:8080", "HTTP listen address; use :8080 to listen on every interface")
+	token := flags.String("token", "", "bearer token required by POST /scans (default: serve_token from the config file)")
+	interval := flags.Duration("interval", 10*time.Minute, "time between scheduled scans")
+	jitter := flags.Float64("jitter", 0.1, "randomize each interval by up to this fraction, e.g. 0.1 for ±10%")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	names := splitList(opts.platformName)
+	if len(names) == 0 {
+		return errors.New("--platform is required")
+	}
+	if len(names) > 1 && (len(opts.apiKeys) > 0 || opts.keyFile != "") {
+		return errors.New("--api-key and --key-file can only be used with a single platform; set each platform's environment variable instead")
+	}
+	if *interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	if *jitter < 0 || *jitter >= 1 {
+		return errors.New("--jitter must be at least 0 and less than 1")
+	}
+
+	if opts.configFile != "" {
+		cfg, err := config.Load(opts.configFile)
+		if err != nil {
+			return err
+		}
+		opts.historyDir = cmp.Or(opts.historyDir, cfg.HistoryDir)
+		*token = cmp.Or(*token, os.ExpandEnv(cfg.ServeToken))
+	}
+	var store *history.Store
+	if opts.historyDir != "" {
+		var err error
+		if store, err = history.Open(opts.historyDir); err != nil {
+			return err
+		}
+	}
+
+	targets, err := serveTargets(opts, selection, names)
+	if err != nil {
+		return err
+	}
+	srv, err := server.New(server.Options{
+		Targets:  targets,
+		Interval: *interval,
+		Jitter:   *jitter,
+		History:  store,
+		Log:      os.Stderr,
+		Token:    *token,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "serving %s on %s\n", strings.Join(names, ", "), *listen)
+	if *token == "" && !loopback(*listen) {
+		fmt.Fprintf(os.Stderr, "warning: %s is reachable from other hosts and anyone can start scans with your API keys; set --token\n", *listen)
+	}
+	return srv.ListenAndServe(ctx, *listen)
+}
+
+// serveTargets builds an engine per platform. Each platform gets its own copy
+// of the engine options so keys and history records stay separate.
+func serveTargets(opts *engineOptions, selection *selectionOptions, names []string) ([]server.Target, error) {
+	targets := make([]server.Target, 0, len(names))
+	for _, name := range names {
+		platformOpts := *opts
+		platformOpts.platformName = name
+		engine, err := platformOpts.engine()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		selector, err := selection.selector(&platformOpts, engine.Platform)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, server.Target{
+			Engine:   engine,
+			Selector: selector,
+			Record: func(started time.Time, results []platform.ProbeResult) error {
+				return platformOpts.record(platformOpts.platformName, started, results)
+			},
+		})
+	}
+	return targets, nil
+}
+
+// loopback reports whether addr only accepts connections from this host.
+func loopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestRunServeRejectsInvalidOptions(t *testing.T) {
+	for _, args := range [][]string{
+		{"--listen", ":0"},
+		{"--platform", "dashscope,deepseek", "--api-key", "secret"},
+		{"--platform", "dashscope", "--api-key", "secret", "--interval", "0s"},
+		{"--platform", "dashscope", "--api-key", "secret", "--jitter", "-1"},
+	} {
+		if err := RunServe(args); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestServeTargetsPerPlatform(t *testing.T) {
+	useFakePlatform(t)
+	t.Setenv("DASHSCOPE_API_KEY", "dashscope-key")
+	t.Setenv("DEEPSEEK_API_KEY", "deepseek-key")
+	dir := t.TempDir()
+
+	opts := &engineOptions{historyDir: dir}
+	targets, err := serveTargets(opts, &selectionOptions{}, []string{"bailian", "deepseek"})
+	if err != nil {
+		t.Fatalf("serve targets: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected a target per platform, got %d", len(targets))
+	}
+	results := []platform.ProbeResult{{Platform: "fake", Model: "ok-model", Status: "ok", Available: true}}
+	for _, target := range targets {
+		if err := target.Record(time.Now(), results); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	store, err := history.Open(dir)
+	if err != nil {
+		t.Fatalf("open history: %v", err)
+	}
+	scans, err := store.Query(history.Query{})
+	if err != nil || len(scans) != 2 {
+		t.Fatalf("expected a history record per platform, got %d (%v)", len(scans), err)
+	}
+	if scans[0].KeyFingerprint == scans[1].KeyFingerprint {
+		t.Fatalf("expected per-platform key fingerprints, got %+v", scans)
+	}
+	platforms := []string{scans[0].Platform, scans[1].Platform}
+	slices.Sort(platforms)
+	if !slices.Equal(platforms, []string{"dashscope", "deepseek"}) {
+		t.Fatalf("expected canonical platform names, got %v", platforms)
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8080": true,
+		"[::1]:8080":     true,
+		"localhost:8080": true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"10.0.0.5:8080":  false,
+		"8080":           false,
+	} {
+		if got := loopback(addr); got != want {
+			t.Fatalf("loopback(%q): expected %t, got %t", addr, want, got)
+		}
+	}
+}
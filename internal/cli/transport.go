@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"cmp"
+	"flag"
+	"net/http"
+	"os"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// transportFlags override the connection settings of every platform in the
+// config file.
+type transportFlags struct {
+	proxy              string
+	caFile             string
+	clientCert         string
+	clientKey          string
+	insecureSkipVerify bool
+}
+
+func registerTransportFlags(flags *flag.FlagSet, opts *transportFlags) {
+	flags.StringVar(&opts.proxy, "proxy", "", "proxy URL (http, https, socks5 or socks5h), or 'direct' to ignore HTTPS_PROXY and friends")
+	flags.StringVar(&opts.caFile, "ca-file", "", "PEM bundle of extra CA certificates to trust")
+	flags.StringVar(&opts.clientCert, "client-cert", "", "PEM client certificate for mTLS")
+	flags.StringVar(&opts.clientKey, "client-key", "", "PEM private key of --client-cert")
+	flags.BoolVar(&opts.insecureSkipVerify, "insecure-skip-verify", false, "do not verify TLS certificates (lab gateways only)")
+}
+
+// transportOptions merges the flags over the platform's config file entry.
+func (o *engineOptions) transportOptions(platformName string) platform.TransportOptions {
+	cfg := o.config.Platform(platformName)
+	return platform.TransportOptions{
+		Proxy:              cmp.Or(o.transport.proxy, os.ExpandEnv(cfg.Proxy)),
+		CAFile:             cmp.Or(o.transport.caFile, cfg.CAFile),
+		CertFile:           cmp.Or(o.transport.clientCert, cfg.ClientCert),
+		KeyFile:            cmp.Or(o.transport.clientKey, cfg.ClientKey),
+		InsecureSkipVerify: o.transport.insecureSkipVerify || cfg.InsecureSkipVerify,
+	}
+}
+
+// httpClient returns the client for the current platform, or nil when no
+// transport option is set so the platform keeps its default client.
+func (o *engineOptions) httpClient() (*http.Client, error) {
+	opts := o.transportOptions(o.platformName)
+	if opts == (platform.TransportOptions{}) {
+		return nil, nil
+	}
+	return platform.NewHTTPClient(o.timeout, opts)
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestTransportOptions(t *testing.T) {
+	t.Setenv("DEEPSEEK_PROXY", "socks5://egress:1080")
+	opts := &engineOptions{
+		platformName: "deepseek",
+		config: &config.Config{Platforms: map[string]config.PlatformConfig{
+			"deepseek": {Proxy: "${DEEPSEEK_PROXY}", CAFile: "corp-ca.pem", ClientCert: "client.pem", ClientKey: "client-key.pem"},
+		}},
+	}
+	want := platform.TransportOptions{Proxy: "socks5://egress:1080", CAFile: "corp-ca.pem", CertFile: "client.pem", KeyFile: "client-key.pem"}
+	if got := opts.transportOptions("deepseek"); got != want {
+		t.Fatalf("config: got %+v, want %+v", got, want)
+	}
+
+	opts.transport = transportFlags{proxy: platform.ProxyDirect, caFile: "lab-ca.pem", insecureSkipVerify: true}
+	want.Proxy, want.CAFile, want.InsecureSkipVerify = platform.ProxyDirect, "lab-ca.pem", true
+	if got := opts.transportOptions("deepseek"); got != want {
+		t.Fatalf("flags: got %+v, want %+v", got, want)
+	}
+
+	if got := opts.transportOptions("dashscope"); got.CertFile != "" || got.Proxy != platform.ProxyDirect {
+		t.Fatalf("other platform: got %+v", got)
+	}
+
+	client, err := (&engineOptions{platformName: "deepseek"}).httpClient()
+	if err != nil || client != nil {
+		t.Fatalf("expected default client, got %v, %v", client, err)
+	}
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/diff"
+	"github.com/NERVEbing/model-scout/internal/filter"
+	"github.com/NERVEbing/model-scout/internal/notify"
+	"github.com/NERVEbing/model-scout/internal/output"
+	"github.com/NERVEbing/model-scout/internal/platform"
+	"github.com/NERVEbing/model-scout/internal/scout"
+)
+
+type watchEvent struct {
+	Time time.Time `json:"time"`
+	diff.Change
+}
+
+// watcher rescans a platform periodically and emits the changes between
+// consecutive scans.
+type watcher struct {
+	engine    scout.Engine
+	selector  scout.Selector
+	opts      *engineOptions
+	expr      filter.Expr
+	interval  time.Duration
+	jitter    float64
+	emit      func(at time.Time, changes []diff.Change) error
+	notifiers []*notify.Notifier
+	log       io.Writer
+}
+
+func RunWatch(args []string) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	opts := &engineOptions{}
+	registerEngineFlags(flags, opts)
+	selection := registerSelectionFlags(flags)
+	interval := flags.Duration("interval", 10*time.Minute, "time between scans")
+	jitter := flags.Float64("jitter", 0.1, "randomize each interval by up to this fraction, e.g. 0.1 for ±10%")
+	outFormat := flags.String("out", "text", "change output format: text or ndjson")
+	outputFile := flags.String("output-file", "", "append changes to this file instead of stdout")
+	var filters filterExpressions
+	flags.Var(&filters, "filter", "only watch results matching an expression (repeatable, combined with and)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if opts.platformName == "" {
+		return errors.New("--platform is required")
+	}
+	if *interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	if *jitter < 0 || *jitter >= 1 {
+		return errors.New("--jitter must be at least 0 and less than 1")
+	}
+	expr, err := parseFilters(filters)
+	if err != nil {
+		return err
+	}
+	format := strings.ToLower(*outFormat)
+	if format != "text" && format != "ndjson" {
+		return fmt.Errorf("unsupported output format: %s", *outFormat)
+	}
+
+	engine, err := opts.engine()
+	if err != nil {
+		return err
+	}
+	selector, err := selection.selector(opts, engine.Platform)
+	if err != nil {
+		return err
+	}
+	notifiers, err := opts.notifiers()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return appendOutput(*outputFile, func(w io.Writer) error {
+		watch := watcher{
+			engine:   engine,
+			selector: selector,
+			opts:     opts,
+			expr:     expr,
+			interval: *interval,
+			jitter:   *jitter,
+			emit: func(at time.Time, changes []diff.Change) error {
+				if format == "ndjson" {
+					for _, change := range changes {
+						if err := output.WriteNDJSON(w, watchEvent{Time: at, Change: change}); err != nil {
+							return err
+						}
+					}
+					return nil
+				}
+				return output.WriteChangeLog(w, at, changes)
+			},
+			notifiers: notifiers,
+			log:       os.Stderr,
+		}
+		return watch.run(ctx)
+	})
+}
+
+// run scans until ctx is canceled. The first scan is the baseline; failed
+// scans are logged and skipped so a transient error is not reported as every
+// model disappearing.
+func (w watcher) run(ctx context.Context) error {
+	var previous []platform.ProbeResult
+	baseline := true
+	for {
+		w.opts.started = time.Now()
+		results, err := w.engine.Scan(ctx, w.selector)
+		if ctx.Err() != nil {
+			return nil
+		}
+		stamp := w.opts.started.Format(time.RFC3339)
+		switch {
+		case err != nil:
+			fmt.Fprintf(w.log, "%s scan failed: %v\n", stamp, err)
+		default:
+			if err := w.opts.record(w.engine.Platform.Name(), w.opts.started, results); err != nil {
+				return err
+			}
+			results = filter.Apply(results, w.expr)
+			if baseline {
+				fmt.Fprintf(w.log, "%s watching %d models, %d available\n", stamp, len(results), countAvailable(results))
+				baseline = false
+			} else if changes := diff.Compare(previous, results); len(changes) > 0 {
+				if err := w.emit(w.opts.started, changes); err != nil {
+					return err
+				}
+				// A failed notification is reported but does not stop the
+				// watch; the next change will be sent normally.
+				if err := notify.Send(ctx, w.notifiers, notify.Event{Time: w.opts.started, Changes: changes}); err != nil {
+					fmt.Fprintf(w.log, "%s notification failed: %v\n", stamp, err)
+				}
+			}
+			previous = results
+		}
+
+		timer := time.NewTimer(scout.Jitter(w.interval, w.jitter, rand.Float64()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+func countAvailable(results []platform.ProbeResult) int {
+	count := 0
+	for _, result := range results {
+		if result.Available {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/diff"
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/platform"
+	"github.com/NERVEbing/model-scout/internal/scout"
+)
+
+// scriptedPlatform serves the next scripted scan on every ListModels call and
+// cancels the watch once the script is exhausted.
+type scriptedPlatform struct {
+	scans   [][]platform.ProbeResult
+	current []platform.ProbeResult
+	cancel  context.CancelFunc
+}
+
+func (p *scriptedPlatform) Name() string {
+	return "fake"
+}
+
+func (p *scriptedPlatform) ListModels(ctx context.Context) ([]platform.Model, error) {
+	if len(p.scans) == 0 {
+		p.cancel()
+		return nil, ctx.Err()
+	}
+	p.current, p.scans = p.scans[0], p.scans[1:]
+	var models []platform.Model
+	for _, result := range p.current {
+		models = append(models, platform.Model{ID: result.Model})
+	}
+	return models, nil
+}
+
+func (p *scriptedPlatform) Probe(_ context.Context, model platform.Model) platform.ProbeResult {
+	for _, result := range p.current {
+		if result.Model == model.ID {
+			return result
+		}
+	}
+	return platform.ProbeResult{}
+}
+
+func TestWatcherEmitsChangesBetweenScans(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ok := func(model string) platform.ProbeResult {
+		return platform.ProbeResult{Platform: "fake", Model: model, Status: "ok", Available: true, LatencyMS: 1}
+	}
+	fail := func(model string) platform.ProbeResult {
+		return platform.ProbeResult{Platform: "fake", Model: model, Status: "fail", Reason: "403 Forbidden", LatencyMS: 1}
+	}
+	fake := &scriptedPlatform{cancel: cancel, scans: [][]platform.ProbeResult{
+		{ok("alpha")},
+		{ok("alpha")},
+		{fail("alpha"), ok("beta")},
+	}}
+
+	historyDir := filepath.Join(t.TempDir(), "history")
+	var batches [][]diff.Change
+	var log bytes.Buffer
+	w := watcher{
+		engine:   scout.Engine{Platform: fake, Workers: 1},
+		opts:     &engineOptions{historyDir: historyDir},
+		interval: time.Millisecond,
+		emit: func(_ time.Time, changes []diff.Change) error {
+			batches = append(batches, changes)
+			return nil
+		},
+		log: &log,
+	}
+
+	if err := w.run(ctx); err != nil {
+		t.Fatalf("expected clean stop, got %v", err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("expected a single batch of changes, got %+v", batches)
+	}
+	var kinds []string
+	for _, change := range batches[0] {
+		kinds = append(kinds, change.Kind+" "+change.Model)
+	}
+	if got := strings.Join(kinds, ","); got != "added beta,now-unavailable alpha" {
+		t.Fatalf("unexpected changes: %s", got)
+	}
+	if !strings.Contains(log.String(), "watching 1 models, 1 available") {
+		t.Fatalf("expected baseline log line, got %q", log.String())
+	}
+
+	store, err := history.Open(historyDir)
+	if err != nil {
+		t.Fatalf("open history: %v", err)
+	}
+	scans, err := store.Query(history.Query{})
+	if err != nil || len(scans) != 3 {
+		t.Fatalf("expected every scan in history, got %d (%v)", len(scans), err)
+	}
+}
+
+func TestRunWatchRejectsInvalidOptions(t *testing.T) {
+	for _, args := range [][]string{
+		{"--interval", "1m"},
+		{"--platform", "deepseek", "--interval", "0s"},
+		{"--platform", "deepseek", "--jitter", "1.5"},
+		{"--platform", "deepseek", "--out", "yaml"},
+	} {
+		if err := RunWatch(args); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestWatchEventJSON(t *testing.T) {
+	data, err := json.Marshal(watchEvent{
+		Time:   time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		Change: diff.Change{Kind: diff.KindAdded, Platform: "fake", Model: "beta"},
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"time":"2026-10-19T09:00:00Z","kind":"added","platform":"fake","model":"beta"}` {
+		t.Fatalf("unexpected event: %s", data)
+	}
+}
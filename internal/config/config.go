@@ -0,0 +1,113 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+type Config struct {
+	// HistoryDir records every scan and probe in this directory, as if
+	// --history-dir had been given.
+	HistoryDir string `yaml:"history_dir"`
+	// ServeToken is the bearer token serve requires to start scans, as if
+	// --token had been given. It may reference environment variables as
+	// ${NAME}.
+	ServeToken string                    `yaml:"serve_token"`
+	Platforms  map[string]PlatformConfig `yaml:"platforms"`
+	Notifiers  []NotifierConfig          `yaml:"notifiers"`
+}
+
+type PlatformConfig struct {
+	// DefaultExcludes replaces the platform's built-in exclusion patterns
+	// when set. An empty list disables them.
+	DefaultExcludes *[]string `yaml:"default_excludes"`
+	// Keys lists API keys to probe with, each under its own label, when
+	// neither --api-key nor --key-file is given. Key values may reference
+	// environment variables as ${NAME}.
+	Keys []KeyConfig `yaml:"keys"`
+	// Proxy is an http, https, socks5 or socks5h URL for this platform, or
+	// "direct" to ignore the proxy environment variables. It may reference
+	// environment variables as ${NAME}.
+	Proxy string `yaml:"proxy"`
+	// CAFile, ClientCert and ClientKey are PEM files: extra trusted CAs and
+	// a client certificate for mTLS.
+	CAFile             string `yaml:"ca_file"`
+	ClientCert         string `yaml:"client_cert"`
+	ClientKey          string `yaml:"client_key"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+type KeyConfig struct {
+	Label string `yaml:"label"`
+	Key   string `yaml:"key"`
+}
+
+// NotifierConfig describes where to send availability changes. URL, Secret
+// and header values may reference environment variables as ${NAME}.
+type NotifierConfig struct {
+	Name string `yaml:"name"`
+	// Type selects the payload shape: webhook, slack, feishu (or lark) or
+	// dingtalk.
+	Type    string            `yaml:"type"`
+	URL     string            `yaml:"url"`
+	Secret  string            `yaml:"secret"`
+	Headers map[string]string `yaml:"headers"`
+	// Models and Events restrict the changes sent; empty means all. Models
+	// takes the same patterns as --include.
+	Models   []string `yaml:"models"`
+	Events   []string `yaml:"events"`
+	Template string   `yaml:"template"`
+	// Retries is the number of extra delivery attempts after a failure; nil
+	// uses the default.
+	Retries      *int          `yaml:"retries"`
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+}
+
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	normalized := make(map[string]PlatformConfig, len(cfg.Platforms))
+	for name, platformCfg := range cfg.Platforms {
+		descriptor, err := platform.Lookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("parse config: platforms: %w", err)
+		}
+		if _, ok := normalized[descriptor.Name]; ok {
+			return nil, fmt.Errorf("parse config: platforms: %s is configured more than once", descriptor.Name)
+		}
+		normalized[descriptor.Name] = platformCfg
+	}
+	cfg.Platforms = normalized
+	return cfg, nil
+}
+
+func (c *Config) Platform(name string) PlatformConfig {
+	if c == nil {
+		return PlatformConfig{}
+	}
+	if descriptor, err := platform.Lookup(name); err == nil {
+		name = descriptor.Name
+	}
+	return c.Platforms[strings.ToLower(name)]
+}
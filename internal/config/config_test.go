@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/NERVEbing/model-scout/internal/platform/all"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte(`
+history_dir: /var/lib/model-scout
+serve_token: ${MODEL_SCOUT_TOKEN}
+platforms:
+  DashScope:
+    default_excludes: [image, "re:-audio-"]
+    keys:
+      - label: cn
+        key: ${DASHSCOPE_CN_KEY}
+      - label: intl
+        key: sk-intl
+    proxy: socks5://proxy.internal:1080
+    ca_file: /etc/ssl/corp-ca.pem
+    client_cert: /etc/model-scout/client.pem
+    client_key: /etc/model-scout/client-key.pem
+    insecure_skip_verify: true
+  deepseek:
+    default_excludes: []
+`)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cfg.HistoryDir != "/var/lib/model-scout" {
+		t.Fatalf("unexpected history dir: %q", cfg.HistoryDir)
+	}
+	if cfg.ServeToken != "${MODEL_SCOUT_TOKEN}" {
+		t.Fatalf("unexpected serve token: %q", cfg.ServeToken)
+	}
+	dashscope := cfg.Platform("dashscope")
+	if dashscope.DefaultExcludes == nil || len(*dashscope.DefaultExcludes) != 2 {
+		t.Fatalf("unexpected dashscope excludes: %#v", dashscope.DefaultExcludes)
+	}
+	if len(dashscope.Keys) != 2 || dashscope.Keys[0] != (KeyConfig{Label: "cn", Key: "${DASHSCOPE_CN_KEY}"}) {
+		t.Fatalf("unexpected dashscope keys: %#v", dashscope.Keys)
+	}
+	if dashscope.Proxy != "socks5://proxy.internal:1080" || dashscope.CAFile != "/etc/ssl/corp-ca.pem" ||
+		dashscope.ClientCert != "/etc/model-scout/client.pem" || dashscope.ClientKey != "/etc/model-scout/client-key.pem" || !dashscope.InsecureSkipVerify {
+		t.Fatalf("unexpected dashscope transport: %#v", dashscope)
+	}
+	deepseek := cfg.Platform("deepseek")
+	if deepseek.DefaultExcludes == nil || len(*deepseek.DefaultExcludes) != 0 {
+		t.Fatalf("expected empty deepseek excludes, got %#v", deepseek.DefaultExcludes)
+	}
+	if other := cfg.Platform("other"); other.DefaultExcludes != nil {
+		t.Fatalf("expected unset excludes, got %#v", other.DefaultExcludes)
+	}
+	if alias := cfg.Platform("bailian"); alias.Proxy != "socks5://proxy.internal:1080" {
+		t.Fatalf("expected the alias to resolve to dashscope, got %+v", alias)
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	cfg, err := Parse(nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Platform("dashscope").DefaultExcludes != nil {
+		t.Fatalf("expected unset excludes")
+	}
+}
+
+func TestParseUnknownField(t *testing.T) {
+	_, err := Parse([]byte("platforms:\n  dashscope:\n    default_exclude: [image]\n"))
+	if err == nil {
+		t.Fatalf("expected error for unknown field")
+	}
+}
+
+func TestParsePlatformAliases(t *testing.T) {
+	cfg, err := Parse([]byte("platforms:\n  Bailian:\n    default_excludes: [image]\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := cfg.Platform("dashscope").DefaultExcludes; got == nil || len(*got) != 1 {
+		t.Fatalf("expected the bailian block to configure dashscope, got %v", got)
+	}
+
+	for _, data := range []string{
+		"platforms:\n  openai:\n    proxy: direct\n",
+		"platforms:\n  dashscope:\n    proxy: direct\n  bailian:\n    proxy: direct\n",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Fatalf("expected error for %q", data)
+		}
+	}
+}
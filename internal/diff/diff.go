@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+const (
+	KindAdded          = "added"
+	KindRemoved        = "removed"
+	KindNowAvailable   = "now-available"
+	KindNowUnavailable = "now-unavailable"
+	KindReasonChanged  = "reason-changed"
+)
+
+// Kinds lists change kinds in the order they are reported.
+var Kinds = []string{KindAdded, KindRemoved, KindNowAvailable, KindNowUnavailable, KindReasonChanged}
+
+type Change struct {
+	Kind     string                `json:"kind" yaml:"kind"`
+	Platform string                `json:"platform" yaml:"platform"`
+	Model    string                `json:"model" yaml:"model"`
+	Key      string                `json:"key,omitempty" yaml:"key,omitempty"`
+	Old      *platform.ProbeResult `json:"old,omitempty" yaml:"old,omitempty"`
+	New      *platform.ProbeResult `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// Regression reports whether the change makes a previously usable model
+// unusable: it became unavailable, or it was available and disappeared.
+func (c Change) Regression() bool {
+	switch c.Kind {
+	case KindNowUnavailable:
+		return true
+	case KindRemoved:
+		return c.Old != nil && c.Old.Available
+	default:
+		return false
+	}
+}
+
+type key struct {
+	platform string
+	model    string
+	apiKey   string
+}
+
+func keyOf(result platform.ProbeResult) key {
+	return key{platform: result.Platform, model: result.Model, apiKey: result.Key}
+}
+
+// Compare matches results by platform, model and API key label and returns the changes from
+// oldResults to newResults, ordered by kind, then platform and model.
+func Compare(oldResults, newResults []platform.ProbeResult) []Change {
+	oldByKey := make(map[key]platform.ProbeResult, len(oldResults))
+	for _, result := range oldResults {
+		oldByKey[keyOf(result)] = result
+	}
+	newByKey := make(map[key]platform.ProbeResult, len(newResults))
+	for _, result := range newResults {
+		newByKey[keyOf(result)] = result
+	}
+
+	var changes []Change
+	for k, after := range newByKey {
+		before, ok := oldByKey[k]
+		change := Change{Platform: k.platform, Model: k.model, Key: k.apiKey, New: &after}
+		switch {
+		case !ok:
+			change.Kind = KindAdded
+		case !before.Available && after.Available:
+			change.Kind = KindNowAvailable
+		case before.Available && !after.Available:
+			change.Kind = KindNowUnavailable
+		case !before.Available && !after.Available && (before.Reason != after.Reason || before.Status != after.Status):
+			change.Kind = KindReasonChanged
+		default:
+			continue
+		}
+		if ok {
+			change.Old = &before
+		}
+		changes = append(changes, change)
+	}
+	for k, before := range oldByKey {
+		if _, ok := newByKey[k]; ok {
+			continue
+		}
+		changes = append(changes, Change{Kind: KindRemoved, Platform: k.platform, Model: k.model, Key: k.apiKey, Old: &before})
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(Kinds, a.Kind), slices.Index(Kinds, b.Kind)),
+			cmp.Compare(a.Platform, b.Platform),
+			cmp.Compare(a.Model, b.Model),
+			cmp.Compare(a.Key, b.Key),
+		)
+	})
+	return changes
+}
+
+func HasRegression(changes []Change) bool {
+	return slices.ContainsFunc(changes, Change.Regression)
+}
+
+// Count returns the number of changes of each kind, including zero counts.
+func Count(changes []Change) map[string]int {
+	counts := make(map[string]int, len(Kinds))
+	for _, kind := range Kinds {
+		counts[kind] = 0
+	}
+	for _, change := range changes {
+		counts[change.Kind]++
+	}
+	return counts
+}
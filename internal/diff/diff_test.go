@@ -0,0 +1,90 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestCompare(t *testing.T) {
+	before := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true},
+		{Platform: "dashscope", Model: "qwen-max", Status: "ok", Available: true},
+		{Platform: "dashscope", Model: "qwen-mini", Status: "fail", Reason: "403 Forbidden"},
+		{Platform: "dashscope", Model: "qwen-long", Status: "fail", Reason: "quota exceeded"},
+		{Platform: "dashscope", Model: "qwen-old", Status: "ok", Available: true},
+		{Platform: "dashscope", Model: "qwen-same", Status: "fail", Reason: "same"},
+	}
+	after := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, LatencyMS: 900},
+		{Platform: "dashscope", Model: "qwen-max", Status: "fail", Reason: "429 Too Many Requests"},
+		{Platform: "dashscope", Model: "qwen-mini", Status: "ok", Available: true},
+		{Platform: "dashscope", Model: "qwen-long", Status: "fail", Reason: "403 Forbidden"},
+		{Platform: "dashscope", Model: "qwen-same", Status: "fail", Reason: "same"},
+		{Platform: "deepseek", Model: "qwen-plus", Status: "ok", Available: true},
+	}
+
+	changes := Compare(before, after)
+	want := []struct {
+		kind     string
+		platform string
+		model    string
+	}{
+		{KindAdded, "deepseek", "qwen-plus"},
+		{KindRemoved, "dashscope", "qwen-old"},
+		{KindNowAvailable, "dashscope", "qwen-mini"},
+		{KindNowUnavailable, "dashscope", "qwen-max"},
+		{KindReasonChanged, "dashscope", "qwen-long"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %#v", len(want), changes)
+	}
+	for i, w := range want {
+		got := changes[i]
+		if got.Kind != w.kind || got.Platform != w.platform || got.Model != w.model {
+			t.Fatalf("change %d: expected %v, got %s %s %s", i, w, got.Kind, got.Platform, got.Model)
+		}
+	}
+	if changes[0].Old != nil || changes[0].New == nil {
+		t.Fatalf("added change should only have new result")
+	}
+	if changes[1].Old == nil || changes[1].New != nil {
+		t.Fatalf("removed change should only have old result")
+	}
+	if !HasRegression(changes) {
+		t.Fatalf("expected regression")
+	}
+	if counts := Count(changes); counts[KindAdded] != 1 || counts[KindReasonChanged] != 1 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+}
+
+func TestCompareKeys(t *testing.T) {
+	before := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-max", Key: "cn", Status: "ok", Available: true},
+		{Platform: "dashscope", Model: "qwen-max", Key: "intl", Status: "ok", Available: true},
+	}
+	after := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-max", Key: "cn", Status: "ok", Available: true},
+		{Platform: "dashscope", Model: "qwen-max", Key: "intl", Status: "fail", Reason: "403 Forbidden"},
+	}
+
+	changes := Compare(before, after)
+	if len(changes) != 1 || changes[0].Kind != KindNowUnavailable || changes[0].Key != "intl" {
+		t.Fatalf("expected only the intl key to regress, got %+v", changes)
+	}
+}
+
+func TestRegression(t *testing.T) {
+	removedUnavailable := Change{Kind: KindRemoved, Old: &platform.ProbeResult{Available: false}}
+	if removedUnavailable.Regression() {
+		t.Fatalf("removing an unavailable model is not a regression")
+	}
+	removedAvailable := Change{Kind: KindRemoved, Old: &platform.ProbeResult{Available: true}}
+	if !removedAvailable.Regression() {
+		t.Fatalf("removing an available model is a regression")
+	}
+	if HasRegression([]Change{{Kind: KindAdded}, {Kind: KindNowAvailable}}) {
+		t.Fatalf("expected no regression")
+	}
+}
@@ -0,0 +1,207 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+type Expr interface {
+	Match(result platform.ProbeResult) bool
+}
+
+// Parse compiles a filter expression such as
+//
+//	available and (latency < 2s or meta.region = cn) and not reason contains quota
+//
+// Errors are *ParseError values that point at the offending column.
+func Parse(input string) (Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{input: input, tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(p.peek(), "empty expression")
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok.describe())
+	}
+	return expr, nil
+}
+
+// ParseAll parses every non-blank input and combines them with "and". It
+// returns nil when there is nothing to filter on.
+func ParseAll(inputs []string) (Expr, error) {
+	var exprs []Expr
+	for _, input := range inputs {
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+		expr, err := Parse(input)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return And(exprs...), nil
+}
+
+// Equal matches results whose field equals any of values, like the
+// expression "name=a,b" but without quoting concerns. It is meant for
+// key=value query parameters.
+func Equal(name string, values ...string) (Expr, error) {
+	f, err := lookupField(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("missing value for %q", name)
+	}
+	cmp := comparison{field: f, op: "="}
+	for _, raw := range values {
+		v, err := f.convert(raw)
+		if err != nil {
+			return nil, err
+		}
+		cmp.values = append(cmp.values, v)
+	}
+	return cmp, nil
+}
+
+// And combines exprs, skipping nil ones. It returns nil when there is nothing
+// to combine.
+func And(exprs ...Expr) Expr {
+	var combined Expr
+	for _, expr := range exprs {
+		switch {
+		case expr == nil:
+		case combined == nil:
+			combined = expr
+		default:
+			combined = andExpr{left: combined, right: expr}
+		}
+	}
+	return combined
+}
+
+func Apply(results []platform.ProbeResult, expr Expr) []platform.ProbeResult {
+	if expr == nil {
+		return results
+	}
+	filtered := make([]platform.ProbeResult, 0, len(results))
+	for _, result := range results {
+		if expr.Match(result) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+type andExpr struct {
+	left, right Expr
+}
+
+func (e andExpr) Match(result platform.ProbeResult) bool {
+	return e.left.Match(result) && e.right.Match(result)
+}
+
+type orExpr struct {
+	left, right Expr
+}
+
+func (e orExpr) Match(result platform.ProbeResult) bool {
+	return e.left.Match(result) || e.right.Match(result)
+}
+
+type notExpr struct {
+	inner Expr
+}
+
+func (e notExpr) Match(result platform.ProbeResult) bool {
+	return !e.inner.Match(result)
+}
+
+type comparison struct {
+	field  field
+	op     string
+	values []value
+	re     *regexp.Regexp
+}
+
+func (c comparison) Match(result platform.ProbeResult) bool {
+	switch c.field.kind {
+	case kindBool:
+		return c.matchAny(func(v value) bool { return result.Available == v.boolean })
+	case kindNumber:
+		return c.matchNumber(float64(result.LatencyMS))
+	case kindList:
+		return c.matchAny(func(v value) bool { return slices.Contains(result.Capabilities, v.text) })
+	default:
+		return c.matchString(c.stringValue(result))
+	}
+}
+
+// matchAny implements "=", "in" and list "contains" as any-of, and "!=" as
+// none-of.
+func (c comparison) matchAny(eq func(value) bool) bool {
+	matched := slices.ContainsFunc(c.values, eq)
+	if c.op == "!=" {
+		return !matched
+	}
+	return matched
+}
+
+func (c comparison) matchNumber(actual float64) bool {
+	expected := c.values[0].number
+	switch c.op {
+	case "<":
+		return actual < expected
+	case "<=":
+		return actual <= expected
+	case ">":
+		return actual > expected
+	case ">=":
+		return actual >= expected
+	default:
+		return c.matchAny(func(v value) bool { return actual == v.number })
+	}
+}
+
+func (c comparison) matchString(actual string) bool {
+	switch c.op {
+	case "contains":
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(c.values[0].text))
+	case "matches":
+		return c.re.MatchString(actual)
+	default:
+		return c.matchAny(func(v value) bool { return actual == v.text })
+	}
+}
+
+func (c comparison) stringValue(result platform.ProbeResult) string {
+	if c.field.metaKey != "" {
+		return result.Meta[c.field.metaKey]
+	}
+	switch c.field.name {
+	case "platform":
+		return result.Platform
+	case "model":
+		return result.Model
+	case "status":
+		return result.Status
+	case "reason":
+		return result.Reason
+	case "key":
+		return result.Key
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,147 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+var results = []platform.ProbeResult{
+	{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, LatencyMS: 320, Capabilities: []string{"chat"}, Meta: map[string]string{"region": "cn"}},
+	{Platform: "dashscope", Model: "qwen-max", Status: "ok", Available: true, LatencyMS: 2400, Capabilities: []string{"chat"}},
+	{Platform: "dashscope", Model: "qwen-mini", Status: "fail", Reason: "403 Forbidden: Quota exceeded", LatencyMS: 80},
+	{Platform: "deepseek", Model: "deepseek-chat", Status: "ok", Available: true, LatencyMS: 900, Capabilities: []string{"chat"}, Meta: map[string]string{"region": "intl"}},
+}
+
+func models(filtered []platform.ProbeResult) string {
+	ids := make([]string, 0, len(filtered))
+	for _, result := range filtered {
+		ids = append(ids, result.Model)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestParseAndMatch(t *testing.T) {
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{expr: "status=ok", want: "qwen-plus,qwen-max,deepseek-chat"},
+		{expr: "status == 'fail'", want: "qwen-mini"},
+		{expr: "status!=ok,fail", want: ""},
+		{expr: "available", want: "qwen-plus,qwen-max,deepseek-chat"},
+		{expr: "!available", want: "qwen-mini"},
+		{expr: "available = false", want: "qwen-mini"},
+		{expr: "latency < 1000", want: "qwen-plus,qwen-mini,deepseek-chat"},
+		{expr: "latency >= 2s", want: "qwen-max"},
+		{expr: "latency<=320 and available", want: "qwen-plus"},
+		{expr: "platform = dashscope and (latency > 2s or not available)", want: "qwen-max,qwen-mini"},
+		{expr: "platform = deepseek or model = qwen-max", want: "qwen-max,deepseek-chat"},
+		{expr: "not (platform = dashscope)", want: "deepseek-chat"},
+		{expr: "platform=dashscope && status=ok || model=deepseek-chat", want: "qwen-plus,qwen-max,deepseek-chat"},
+		{expr: "model contains QWEN", want: "qwen-plus,qwen-max,qwen-mini"},
+		{expr: `model matches "^qwen-(max|plus)$"`, want: "qwen-plus,qwen-max"},
+		{expr: "reason contains quota", want: "qwen-mini"},
+		{expr: "capabilities contains chat", want: "qwen-plus,qwen-max,deepseek-chat"},
+		{expr: "capabilities != chat", want: "qwen-mini"},
+		{expr: "meta.region = cn", want: "qwen-plus"},
+		{expr: "meta.region in (cn, intl)", want: "qwen-plus,deepseek-chat"},
+		{expr: "meta.missing = ''", want: "qwen-plus,qwen-max,qwen-mini,deepseek-chat"},
+		{expr: "status in (fail)", want: "qwen-mini"},
+	}
+	for _, tc := range cases {
+		expr, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.expr, err)
+		}
+		if got := models(Apply(results, expr)); got != tc.want {
+			t.Fatalf("filter %q: expected %q, got %q", tc.expr, tc.want, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		expr   string
+		column int
+		msg    string
+	}{
+		{expr: "status", column: 7, msg: "expected operator"},
+		{expr: "unknown = ok", column: 1, msg: "unknown field"},
+		{expr: "status = ", column: 10, msg: "expected value"},
+		{expr: "available = maybe", column: 13, msg: "invalid boolean"},
+		{expr: "latency < fast", column: 11, msg: "invalid number"},
+		{expr: "status < ok", column: 8, msg: "not supported"},
+		{expr: "(status = ok", column: 13, msg: `expected ")"`},
+		{expr: "status = ok)", column: 12, msg: "unexpected"},
+		{expr: "model matches '('", column: 15, msg: "invalid regular expression"},
+		{expr: "model = 'qwen", column: 9, msg: "unterminated string"},
+		{expr: "status = ok & available", column: 13, msg: "use"},
+		{expr: "meta. = x", column: 1, msg: "missing key"},
+		{expr: "", column: 1, msg: "empty expression"},
+	}
+	for _, tc := range cases {
+		_, err := Parse(tc.expr)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("parse %q: expected ParseError, got %v", tc.expr, err)
+		}
+		if parseErr.Column != tc.column || !strings.Contains(parseErr.Msg, tc.msg) {
+			t.Fatalf("parse %q: expected column %d %q, got column %d %q", tc.expr, tc.column, tc.msg, parseErr.Column, parseErr.Msg)
+		}
+	}
+}
+
+func TestParseErrorPointsAtColumn(t *testing.T) {
+	_, err := Parse("status = ok and latency < fast")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", err.Error())
+	}
+	if strings.Index(lines[2], "^") != strings.Index(lines[1], "fast") {
+		t.Fatalf("caret does not point at value:\n%s", err.Error())
+	}
+}
+
+func TestParseAllCombinesWithAnd(t *testing.T) {
+	expr, err := ParseAll([]string{"platform=dashscope", " ", "status=ok"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := models(Apply(results, expr)); got != "qwen-plus,qwen-max" {
+		t.Fatalf("unexpected result: %q", got)
+	}
+
+	expr, err = ParseAll(nil)
+	if err != nil || expr != nil {
+		t.Fatalf("expected nil expression, got %v, %v", expr, err)
+	}
+}
+
+func TestEqualAndAnd(t *testing.T) {
+	status, err := Equal("status", "ok", "fail")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	region, err := Equal("meta.region", "cn")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := models(Apply(results, And(nil, status, region))); got != "qwen-plus" {
+		t.Fatalf("unexpected result: %q", got)
+	}
+	if And() != nil || And(nil) != nil {
+		t.Fatalf("expected nil expression")
+	}
+
+	for _, tc := range [][]string{{"unknown", "x"}, {"available", "maybe"}, {"status"}} {
+		if _, err := Equal(tc[0], tc[1:]...); err == nil {
+			t.Fatalf("expected error for %v", tc)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// col is the 1-based column of the first character of the token.
+	col int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// isWord reports whether t is the bare keyword kw (case-insensitive).
+func (t token) isWord(kw string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, kw)
+}
+
+const wordStops = " \t\r\n()=!<>,\"'&|"
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		col := i + 1
+		switch {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", col: col})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", col: col})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", col: col})
+			i++
+		case r == '"' || r == '\'':
+			text, next, err := lexString(runes, i)
+			if err != nil {
+				return nil, &ParseError{Input: input, Column: col, Msg: err.Error()}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, col: col})
+			i = next
+		case strings.ContainsRune("=!<>&|", r):
+			op := string(r)
+			if i+1 < len(runes) {
+				pair := string(runes[i : i+2])
+				switch pair {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = pair
+				}
+			}
+			if op == "&" || op == "|" {
+				return nil, &ParseError{Input: input, Column: col, Msg: fmt.Sprintf("unexpected %q (use %q or %q)", op, "and", "or")}
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, col: col})
+			i += len([]rune(op))
+		default:
+			start := i
+			for i < len(runes) && !strings.ContainsRune(wordStops, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), col: col})
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, col: len(runes) + 1})
+	return tokens, nil
+}
+
+func lexString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteRune(runes[i])
+			}
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
@@ -0,0 +1,336 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ParseError struct {
+	Input  string
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	caret := strings.Repeat(" ", max(e.Column-1, 0)) + "^"
+	return fmt.Sprintf("invalid filter at column %d: %s\n  %s\n  %s", e.Column, e.Msg, e.Input, caret)
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return &ParseError{Input: p.input, Column: tok.col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.isWord("or") || (tok.kind == tokenOp && tok.text == "||"); tok = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.isWord("and") || (tok.kind == tokenOp && tok.text == "&&"); tok = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok := p.peek()
+	switch {
+	case tok.isWord("not") || (tok.kind == tokenOp && tok.text == "!"):
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner: inner}, nil
+	case tok.kind == tokenLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorf(closing, "expected \")\", got %s", closing.describe())
+		}
+		return inner, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokenWord || isKeyword(fieldTok.text) {
+		return nil, p.errorf(fieldTok, "expected field name, got %s", fieldTok.describe())
+	}
+	f, err := lookupField(fieldTok.text)
+	if err != nil {
+		return nil, p.errorf(fieldTok, "%v", err)
+	}
+
+	opTok := p.peek()
+	var op string
+	switch {
+	case opTok.kind == tokenOp && opTok.text != "!" && opTok.text != "&&" && opTok.text != "||":
+		op = opTok.text
+		if op == "==" {
+			op = "="
+		}
+	case opTok.isWord("contains"), opTok.isWord("matches"), opTok.isWord("in"):
+		op = strings.ToLower(opTok.text)
+	default:
+		// A bare boolean field such as "available" is shorthand for
+		// "available = true".
+		if f.kind == kindBool {
+			return comparison{field: f, op: "=", values: []value{{boolean: true}}}, nil
+		}
+		return nil, p.errorf(opTok, "expected operator after %q, got %s", fieldTok.text, opTok.describe())
+	}
+	p.next()
+	if !f.supports(op) {
+		return nil, p.errorf(opTok, "operator %q is not supported for %s field %q", op, f.kind, f.name)
+	}
+
+	var rawValues []token
+	switch op {
+	case "in":
+		rawValues, err = p.parseList()
+	case "=", "!=":
+		// Comma-separated values match any of them: status=ok,active.
+		rawValues, err = p.parseValues()
+	default:
+		var tok token
+		tok, err = p.parseValue()
+		rawValues = []token{tok}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := comparison{field: f, op: op}
+	for _, tok := range rawValues {
+		v, err := f.convert(tok.text)
+		if err != nil {
+			return nil, p.errorf(tok, "%v", err)
+		}
+		cmp.values = append(cmp.values, v)
+	}
+	if op == "matches" {
+		re, err := regexp.Compile(rawValues[0].text)
+		if err != nil {
+			return nil, p.errorf(rawValues[0], "invalid regular expression: %v", err)
+		}
+		cmp.re = re
+	}
+	return cmp, nil
+}
+
+func (p *parser) parseValue() (token, error) {
+	tok := p.next()
+	if tok.kind != tokenWord && tok.kind != tokenString {
+		return tok, p.errorf(tok, "expected value, got %s", tok.describe())
+	}
+	return tok, nil
+}
+
+func (p *parser) parseValues() ([]token, error) {
+	first, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	values := []token{first}
+	for p.peek().kind == tokenComma {
+		p.next()
+		tok, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, tok)
+	}
+	return values, nil
+}
+
+func (p *parser) parseList() ([]token, error) {
+	if open := p.next(); open.kind != tokenLParen {
+		return nil, p.errorf(open, "expected \"(\" after in, got %s", open.describe())
+	}
+	values, err := p.parseValues()
+	if err != nil {
+		return nil, err
+	}
+	if closing := p.next(); closing.kind != tokenRParen {
+		return nil, p.errorf(closing, "expected \",\" or \")\", got %s", closing.describe())
+	}
+	return values, nil
+}
+
+func isKeyword(word string) bool {
+	switch strings.ToLower(word) {
+	case "and", "or", "not", "in", "contains", "matches":
+		return true
+	default:
+		return false
+	}
+}
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindBool
+	kindNumber
+	kindList
+)
+
+func (k fieldKind) String() string {
+	switch k {
+	case kindBool:
+		return "boolean"
+	case kindNumber:
+		return "numeric"
+	case kindList:
+		return "list"
+	default:
+		return "string"
+	}
+}
+
+type field struct {
+	name    string
+	kind    fieldKind
+	metaKey string
+}
+
+var fields = []field{
+	{name: "platform", kind: kindString},
+	{name: "model", kind: kindString},
+	{name: "status", kind: kindString},
+	{name: "reason", kind: kindString},
+	{name: "key", kind: kindString},
+	{name: "available", kind: kindBool},
+	{name: "latency", kind: kindNumber},
+	{name: "capabilities", kind: kindList},
+}
+
+// Fields returns the names accepted in filter expressions, excluding the
+// open-ended meta.<key> form.
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+func lookupField(name string) (field, error) {
+	lower := strings.ToLower(name)
+	if key, ok := strings.CutPrefix(lower, "meta."); ok {
+		if key == "" {
+			return field{}, fmt.Errorf("missing key after \"meta.\"")
+		}
+		// Meta keys keep their original case.
+		return field{name: lower, kind: kindString, metaKey: name[len("meta."):]}, nil
+	}
+	for _, f := range fields {
+		if f.name == lower {
+			return f, nil
+		}
+	}
+	return field{}, fmt.Errorf("unknown field %q (fields: %s, meta.<key>)", name, strings.Join(Fields(), ", "))
+}
+
+func (f field) supports(op string) bool {
+	switch f.kind {
+	case kindBool:
+		return op == "=" || op == "!="
+	case kindNumber:
+		return op != "contains" && op != "matches"
+	case kindList:
+		return op == "=" || op == "!=" || op == "contains"
+	default:
+		switch op {
+		case "<", "<=", ">", ">=":
+			return false
+		}
+		return true
+	}
+}
+
+type value struct {
+	text    string
+	boolean bool
+	number  float64
+}
+
+func (f field) convert(raw string) (value, error) {
+	switch f.kind {
+	case kindBool:
+		switch strings.ToLower(raw) {
+		case "true":
+			return value{text: raw, boolean: true}, nil
+		case "false":
+			return value{text: raw}, nil
+		}
+		return value{}, fmt.Errorf("invalid boolean %q", raw)
+	case kindNumber:
+		number, err := parseMillis(raw)
+		if err != nil {
+			return value{}, err
+		}
+		return value{text: raw, number: number}, nil
+	default:
+		return value{text: raw}, nil
+	}
+}
+
+// parseMillis accepts a plain number of milliseconds or a Go duration.
+func parseMillis(raw string) (float64, error) {
+	if number, err := strconv.ParseFloat(raw, 64); err == nil {
+		return number, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q (use milliseconds or a duration such as 1.5s)", raw)
+	}
+	return float64(d) / float64(time.Millisecond), nil
+}
@@ -0,0 +1,197 @@
+package history
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// Scan is one recorded run of scan or probe.
+type Scan struct {
+	ID             string                 `json:"id" yaml:"id"`
+	Time           time.Time              `json:"time" yaml:"time"`
+	Platform       string                 `json:"platform" yaml:"platform"`
+	KeyFingerprint string                 `json:"key_fingerprint,omitempty" yaml:"key_fingerprint,omitempty"`
+	Results        []platform.ProbeResult `json:"results" yaml:"results"`
+}
+
+// Entry is a single probe result together with the scan it belongs to.
+type Entry struct {
+	Time                 time.Time `json:"time" yaml:"time"`
+	ScanID               string    `json:"scan_id" yaml:"scan_id"`
+	KeyFingerprint       string    `json:"key_fingerprint,omitempty" yaml:"key_fingerprint,omitempty"`
+	platform.ProbeResult `yaml:",inline"`
+}
+
+type Query struct {
+	Platform string
+	Model    string
+	// Key matches the label of a result probed with several keys, or else
+	// the fingerprint of the scan's key.
+	Key   string
+	Since time.Time
+	Until time.Time
+}
+
+// Store keeps scans in an append-only directory of JSON Lines files, one file
+// per UTC day named scans-YYYY-MM-DD.jsonl. Each line is a Scan.
+type Store struct {
+	dir string
+}
+
+const (
+	filePrefix = "scans-"
+	fileSuffix = ".jsonl"
+	dayLayout  = "2006-01-02"
+)
+
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Append records scan, assigning an ID and time if they are unset.
+func (s *Store) Append(scan Scan) (Scan, error) {
+	if scan.Time.IsZero() {
+		scan.Time = time.Now()
+	}
+	scan.Time = scan.Time.UTC()
+	if scan.ID == "" {
+		scan.ID = scan.Time.Format("20060102T150405.000Z") + "-" + scan.Platform
+	}
+	data, err := json.Marshal(scan)
+	if err != nil {
+		return Scan{}, err
+	}
+
+	path := filepath.Join(s.dir, filePrefix+scan.Time.Format(dayLayout)+fileSuffix)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return Scan{}, err
+	}
+	// A single write per scan keeps concurrent appends from interleaving.
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return Scan{}, err
+	}
+	return scan, file.Close()
+}
+
+// Query returns matching scans in chronological order. When q.Model or q.Key
+// is set, each scan only keeps the matching results and scans without any are
+// dropped.
+func (s *Store) Query(q Query) ([]Scan, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, filePrefix+"*"+fileSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	var scans []Scan
+	for _, path := range paths {
+		day, err := time.Parse(dayLayout, strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), filePrefix), fileSuffix))
+		if err != nil {
+			continue
+		}
+		if !q.Since.IsZero() && day.Add(24*time.Hour).Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && day.After(q.Until) {
+			continue
+		}
+		fileScans, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, scan := range fileScans {
+			if q.matches(&scan) {
+				scans = append(scans, scan)
+			}
+		}
+	}
+	slices.SortStableFunc(scans, func(a, b Scan) int {
+		return cmp.Or(a.Time.Compare(b.Time), cmp.Compare(a.ID, b.ID))
+	})
+	return scans, nil
+}
+
+func (q Query) matches(scan *Scan) bool {
+	if q.Platform != "" && !strings.EqualFold(scan.Platform, q.Platform) {
+		return false
+	}
+	if !q.Since.IsZero() && scan.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && scan.Time.After(q.Until) {
+		return false
+	}
+	if q.Model != "" || q.Key != "" {
+		var kept []platform.ProbeResult
+		for _, result := range scan.Results {
+			if (q.Model == "" || result.Model == q.Model) && (q.Key == "" || cmp.Or(result.Key, scan.KeyFingerprint) == q.Key) {
+				kept = append(kept, result)
+			}
+		}
+		if len(kept) == 0 {
+			return false
+		}
+		scan.Results = kept
+	}
+	return true
+}
+
+func readFile(path string) ([]Scan, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var scans []Scan
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Bytes()
+		if len(strings.TrimSpace(string(text))) == 0 {
+			continue
+		}
+		var scan Scan
+		if err := json.Unmarshal(text, &scan); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		scans = append(scans, scan)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return scans, nil
+}
+
+// Entries flattens scans into one entry per result, in scan order.
+func Entries(scans []Scan) []Entry {
+	var entries []Entry
+	for _, scan := range scans {
+		for _, result := range scan.Results {
+			entries = append(entries, Entry{
+				Time:           scan.Time,
+				ScanID:         scan.ID,
+				KeyFingerprint: scan.KeyFingerprint,
+				ProbeResult:    result,
+			})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,103 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestStoreAppendAndQuery(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+
+	day1 := time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC)
+	day2 := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	scans := []Scan{
+		{Time: day2, Platform: "dashscope", KeyFingerprint: "sha256:aaaa", Results: []platform.ProbeResult{
+			{Platform: "dashscope", Model: "qwen-plus", Status: "fail"},
+			{Platform: "dashscope", Model: "qwen-max", Status: "ok", Available: true},
+		}},
+		{Time: day1, Platform: "dashscope", Results: []platform.ProbeResult{
+			{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true},
+		}},
+		{Time: day2.Add(time.Hour), Platform: "deepseek", Results: []platform.ProbeResult{
+			{Platform: "deepseek", Model: "deepseek-chat", Key: "cn", Status: "ok", Available: true},
+			{Platform: "deepseek", Model: "deepseek-chat", Key: "intl", Status: "fail"},
+		}},
+	}
+	for _, scan := range scans {
+		recorded, err := store.Append(scan)
+		if err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		if recorded.ID == "" {
+			t.Fatalf("expected scan ID to be assigned")
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "scans-*.jsonl"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("expected one file per day, got %v (%v)", files, err)
+	}
+
+	all, err := store.Query(Query{})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(all) != 3 || !all[0].Time.Equal(day1) || all[2].Platform != "deepseek" {
+		t.Fatalf("expected chronological scans, got %+v", all)
+	}
+
+	byModel, err := store.Query(Query{Platform: "DashScope", Model: "qwen-plus"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	entries := Entries(byModel)
+	if len(entries) != 2 || entries[0].Status != "ok" || entries[1].Status != "fail" || entries[1].KeyFingerprint != "sha256:aaaa" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	byKey, err := store.Query(Query{Key: "sha256:aaaa"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(byKey) != 1 || len(byKey[0].Results) != 2 {
+		t.Fatalf("unexpected scans for key fingerprint: %+v", byKey)
+	}
+
+	windowed, err := store.Query(Query{Since: day2.Add(-time.Minute), Until: day2.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(windowed) != 1 || !windowed[0].Time.Equal(day2) {
+		t.Fatalf("unexpected windowed scans: %+v", windowed)
+	}
+
+	byLabel, err := store.Query(Query{Platform: "deepseek", Key: "intl"})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(byLabel) != 1 || len(byLabel[0].Results) != 1 || byLabel[0].Results[0].Status != "fail" {
+		t.Fatalf("unexpected scans for key label: %+v", byLabel)
+	}
+}
+
+func TestQueryReportsCorruptLine(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "scans-2026-10-19.jsonl"), []byte("{}\n{oops\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := store.Query(Query{}); err == nil {
+		t.Fatalf("expected error for corrupt line")
+	}
+}
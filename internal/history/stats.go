@@ -0,0 +1,156 @@
+package history
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// DefaultFlapThreshold is the number of ok/fail transitions within a window
+// at which a model is considered flapping.
+const DefaultFlapThreshold = 3
+
+type SummaryOptions struct {
+	// FlapThreshold is the minimum number of transitions between available
+	// and unavailable for a model to be flagged as flapping.
+	FlapThreshold int
+}
+
+// Summary describes model reliability over a set of scans.
+type Summary struct {
+	Since  time.Time    `json:"since" yaml:"since"`
+	Until  time.Time    `json:"until" yaml:"until"`
+	Scans  int          `json:"scans" yaml:"scans"`
+	Models []ModelStats `json:"models" yaml:"models"`
+}
+
+type ModelStats struct {
+	Platform string `json:"platform" yaml:"platform"`
+	Model    string `json:"model" yaml:"model"`
+	// Key is the label or fingerprint of the key the model was probed with,
+	// or the scan's key fingerprint for single-key scans.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+	// Samples is the number of scans that probed the model.
+	Samples          int `json:"samples" yaml:"samples"`
+	AvailableSamples int `json:"available_samples" yaml:"available_samples"`
+	// Availability is the percentage of samples in which the model was
+	// available.
+	Availability float64 `json:"availability" yaml:"availability"`
+	// LongestOutageMS runs from the first unavailable sample to the next
+	// available one, or to the last sample if the outage is ongoing.
+	// OutageOngoing reports whether that longest outage has not ended.
+	LongestOutageMS int64 `json:"longest_outage_ms" yaml:"longest_outage_ms"`
+	OutageOngoing   bool  `json:"outage_ongoing" yaml:"outage_ongoing"`
+	// Latencies only count available samples; failures are often fast
+	// rejections that would skew the mean.
+	MeanLatencyMS       int64 `json:"mean_latency_ms" yaml:"mean_latency_ms"`
+	FirstHalfLatencyMS  int64 `json:"first_half_latency_ms" yaml:"first_half_latency_ms"`
+	SecondHalfLatencyMS int64 `json:"second_half_latency_ms" yaml:"second_half_latency_ms"`
+	// LatencyChange is the percentage change from the first half of the
+	// samples to the second, or 0 when either half has no latency.
+	LatencyChange float64   `json:"latency_change" yaml:"latency_change"`
+	Transitions   int       `json:"transitions" yaml:"transitions"`
+	Flapping      bool      `json:"flapping" yaml:"flapping"`
+	LastStatus    string    `json:"last_status" yaml:"last_status"`
+	LastSeen      time.Time `json:"last_seen" yaml:"last_seen"`
+}
+
+// Summarize computes per-model statistics from scans, which must be in
+// chronological order as returned by Query. Results probed with different
+// keys, or from scans with different key fingerprints, are summarized
+// separately. Models are sorted by platform, model ID and
+// key.
+func Summarize(scans []Scan, opts SummaryOptions) Summary {
+	threshold := opts.FlapThreshold
+	if threshold <= 0 {
+		threshold = DefaultFlapThreshold
+	}
+
+	type key struct{ platform, model, key string }
+	var order []key
+	samples := make(map[key][]Entry)
+	for _, entry := range Entries(scans) {
+		k := key{entry.Platform, entry.Model, cmp.Or(entry.Key, entry.KeyFingerprint)}
+		if _, ok := samples[k]; !ok {
+			order = append(order, k)
+		}
+		samples[k] = append(samples[k], entry)
+	}
+
+	summary := Summary{Scans: len(scans), Models: make([]ModelStats, 0, len(order))}
+	if len(scans) > 0 {
+		summary.Since = scans[0].Time
+		summary.Until = scans[len(scans)-1].Time
+	}
+	for _, k := range order {
+		stats := modelStats(samples[k])
+		stats.Key = k.key
+		stats.Flapping = stats.Transitions >= threshold
+		summary.Models = append(summary.Models, stats)
+	}
+	slices.SortFunc(summary.Models, func(a, b ModelStats) int {
+		return cmp.Or(cmp.Compare(a.Platform, b.Platform), cmp.Compare(a.Model, b.Model), cmp.Compare(a.Key, b.Key))
+	})
+	return summary
+}
+
+func modelStats(entries []Entry) ModelStats {
+	last := entries[len(entries)-1]
+	stats := ModelStats{
+		Platform:   last.Platform,
+		Model:      last.Model,
+		Samples:    len(entries),
+		LastStatus: last.Status,
+		LastSeen:   last.Time,
+	}
+
+	var outageStart time.Time
+	inOutage := false
+	for i, entry := range entries {
+		if i > 0 && entry.Available != entries[i-1].Available {
+			stats.Transitions++
+		}
+		if entry.Available {
+			stats.AvailableSamples++
+			if inOutage {
+				stats.LongestOutageMS = max(stats.LongestOutageMS, entry.Time.Sub(outageStart).Milliseconds())
+				inOutage = false
+			}
+			continue
+		}
+		if !inOutage {
+			outageStart = entry.Time
+			inOutage = true
+		}
+	}
+	if inOutage {
+		if ongoing := last.Time.Sub(outageStart).Milliseconds(); ongoing >= stats.LongestOutageMS {
+			stats.LongestOutageMS = ongoing
+			stats.OutageOngoing = true
+		}
+	}
+	stats.Availability = float64(stats.AvailableSamples) / float64(stats.Samples) * 100
+
+	half := len(entries) / 2
+	stats.MeanLatencyMS = meanLatency(entries)
+	stats.FirstHalfLatencyMS = meanLatency(entries[:half])
+	stats.SecondHalfLatencyMS = meanLatency(entries[half:])
+	if stats.FirstHalfLatencyMS > 0 && stats.SecondHalfLatencyMS > 0 {
+		stats.LatencyChange = float64(stats.SecondHalfLatencyMS-stats.FirstHalfLatencyMS) / float64(stats.FirstHalfLatencyMS) * 100
+	}
+	return stats
+}
+
+func meanLatency(entries []Entry) int64 {
+	var total, count int64
+	for _, entry := range entries {
+		if entry.Available && entry.LatencyMS > 0 {
+			total += entry.LatencyMS
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / count
+}
@@ -0,0 +1,126 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func scansOf(start time.Time, statuses map[string]string) []Scan {
+	var scans []Scan
+	for model, sequence := range statuses {
+		for i, status := range sequence {
+			for len(scans) <= i {
+				scans = append(scans, Scan{Time: start.Add(time.Duration(len(scans)) * time.Hour), Platform: "dashscope"})
+			}
+			result := platform.ProbeResult{Platform: "dashscope", Model: model, Status: "fail"}
+			if status == '+' {
+				result = platform.ProbeResult{Platform: "dashscope", Model: model, Status: "ok", Available: true, LatencyMS: int64(100 * (i + 1))}
+			}
+			if status != ' ' {
+				scans[i].Results = append(scans[i].Results, result)
+			}
+		}
+	}
+	return scans
+}
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	scans := scansOf(start, map[string]string{
+		"stable":   "++++++",
+		"flapping": "+-+-+-",
+		"outage":   "+---+-",
+		"down":     "++ ---",
+	})
+
+	summary := Summarize(scans, SummaryOptions{})
+	if summary.Scans != 6 || !summary.Since.Equal(start) || !summary.Until.Equal(start.Add(5*time.Hour)) {
+		t.Fatalf("unexpected window: %+v", summary)
+	}
+	byModel := make(map[string]ModelStats)
+	for _, stats := range summary.Models {
+		byModel[stats.Model] = stats
+	}
+	if got := summary.Models[0].Model; got != "down" {
+		t.Fatalf("expected models sorted by ID, got %q first", got)
+	}
+
+	stable := byModel["stable"]
+	if stable.Availability != 100 || stable.LongestOutageMS != 0 || stable.Flapping || stable.MeanLatencyMS != 350 {
+		t.Fatalf("unexpected stable stats: %+v", stable)
+	}
+	if stable.FirstHalfLatencyMS != 200 || stable.SecondHalfLatencyMS != 500 || stable.LatencyChange != 150 {
+		t.Fatalf("unexpected stable latency trend: %+v", stable)
+	}
+
+	flapping := byModel["flapping"]
+	if flapping.Transitions != 5 || !flapping.Flapping || flapping.Availability != 50 || flapping.LastStatus != "fail" {
+		t.Fatalf("unexpected flapping stats: %+v", flapping)
+	}
+
+	outage := byModel["outage"]
+	if outage.LongestOutageMS != (3*time.Hour).Milliseconds() || outage.OutageOngoing {
+		t.Fatalf("unexpected outage stats: %+v", outage)
+	}
+
+	down := byModel["down"]
+	if down.Samples != 5 || down.LongestOutageMS != (2*time.Hour).Milliseconds() || !down.OutageOngoing || down.Flapping {
+		t.Fatalf("unexpected down stats: %+v", down)
+	}
+
+	if summary := Summarize(scans, SummaryOptions{FlapThreshold: 6}); summary.Models[1].Flapping {
+		t.Fatalf("expected higher threshold to clear flapping flag")
+	}
+}
+
+func TestSummarizeSeparatesKeys(t *testing.T) {
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	var scans []Scan
+	for i := range 4 {
+		scans = append(scans, Scan{Time: start.Add(time.Duration(i) * time.Hour), Platform: "dashscope", Results: []platform.ProbeResult{
+			{Platform: "dashscope", Model: "qwen-max", Key: "cn", Status: "ok", Available: true, LatencyMS: 100},
+			{Platform: "dashscope", Model: "qwen-max", Key: "intl", Status: "fail", Reason: "403 Forbidden"},
+		}})
+	}
+
+	summary := Summarize(scans, SummaryOptions{})
+	if len(summary.Models) != 2 {
+		t.Fatalf("expected one series per key, got %+v", summary.Models)
+	}
+	cn, intl := summary.Models[0], summary.Models[1]
+	if cn.Key != "cn" || cn.Availability != 100 || cn.Samples != 4 || cn.Transitions != 0 || cn.Flapping {
+		t.Fatalf("unexpected cn stats: %+v", cn)
+	}
+	if intl.Key != "intl" || intl.Availability != 0 || intl.Transitions != 0 || intl.Flapping || !intl.OutageOngoing {
+		t.Fatalf("unexpected intl stats: %+v", intl)
+	}
+}
+
+func TestSummarizeSeparatesKeyFingerprints(t *testing.T) {
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	var scans []Scan
+	for i := range 4 {
+		scan := Scan{Time: start.Add(time.Duration(i) * time.Hour), Platform: "dashscope", KeyFingerprint: "sha256:aaaa"}
+		result := platform.ProbeResult{Platform: "dashscope", Model: "qwen-max", Status: "ok", Available: true, LatencyMS: 100}
+		if i%2 == 1 {
+			scan.KeyFingerprint = "sha256:bbbb"
+			result = platform.ProbeResult{Platform: "dashscope", Model: "qwen-max", Status: "denied", Reason: "403 Forbidden"}
+		}
+		scan.Results = []platform.ProbeResult{result}
+		scans = append(scans, scan)
+	}
+
+	summary := Summarize(scans, SummaryOptions{FlapThreshold: 1})
+	if len(summary.Models) != 2 {
+		t.Fatalf("expected one series per key fingerprint, got %+v", summary.Models)
+	}
+	a, b := summary.Models[0], summary.Models[1]
+	if a.Key != "sha256:aaaa" || a.Availability != 100 || a.Samples != 2 || a.Transitions != 0 || a.Flapping {
+		t.Fatalf("unexpected stats for the first key: %+v", a)
+	}
+	if b.Key != "sha256:bbbb" || b.Availability != 0 || b.Samples != 2 || b.Transitions != 0 || b.Flapping {
+		t.Fatalf("unexpected stats for the second key: %+v", b)
+	}
+}
@@ -0,0 +1,239 @@
+package notify
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/diff"
+	"github.com/NERVEbing/model-scout/internal/output"
+	"github.com/NERVEbing/model-scout/internal/scout"
+)
+
+const (
+	TypeWebhook  = "webhook"
+	TypeSlack    = "slack"
+	TypeFeishu   = "feishu"
+	TypeDingTalk = "dingtalk"
+
+	DefaultRetries      = 3
+	DefaultRetryBackoff = time.Second
+)
+
+// DefaultTemplate renders one line per change.
+const DefaultTemplate = `model-scout: {{len .Changes}} model change(s)
+{{range .Changes}}- {{.Kind}}: {{.Platform}}/{{.Model}}{{with .New}} ({{.Status}}{{with .Reason}}: {{.}}{{end}}){{end}}
+{{end}}`
+
+// Event is a batch of changes detected at one point in time.
+type Event struct {
+	Time    time.Time     `json:"time"`
+	Changes []diff.Change `json:"changes"`
+}
+
+// Notifier delivers events to a single endpoint.
+type Notifier struct {
+	name    string
+	kind    string
+	url     string
+	secret  string
+	headers map[string]string
+	models  []scout.Pattern
+	events  []string
+	tmpl    *template.Template
+	retries int
+	backoff time.Duration
+	client  *http.Client
+	now     func() time.Time
+}
+
+// New validates cfg and returns a notifier for it.
+func New(cfg config.NotifierConfig) (*Notifier, error) {
+	n := &Notifier{
+		name:    cfg.Name,
+		kind:    strings.ToLower(cfg.Type),
+		url:     os.ExpandEnv(cfg.URL),
+		secret:  os.ExpandEnv(cfg.Secret),
+		headers: cfg.Headers,
+		retries: DefaultRetries,
+		backoff: cmp.Or(cfg.RetryBackoff, DefaultRetryBackoff),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		now:     time.Now,
+	}
+	if n.kind == "lark" {
+		n.kind = TypeFeishu
+	}
+	if n.name == "" {
+		n.name = n.kind
+	}
+	switch n.kind {
+	case TypeWebhook, TypeSlack, TypeFeishu, TypeDingTalk:
+	case "":
+		return nil, fmt.Errorf("notifier %s: type is required", n.name)
+	default:
+		return nil, fmt.Errorf("notifier %s: unsupported type %q (expected webhook, slack, feishu or dingtalk)", n.name, cfg.Type)
+	}
+	if n.url == "" {
+		return nil, fmt.Errorf("notifier %s: url is required", n.name)
+	}
+	if n.backoff < 0 {
+		return nil, fmt.Errorf("notifier %s: retry_backoff must not be negative", n.name)
+	}
+	if cfg.Retries != nil {
+		if *cfg.Retries < 0 {
+			return nil, fmt.Errorf("notifier %s: retries must not be negative", n.name)
+		}
+		n.retries = *cfg.Retries
+	}
+	models, err := scout.ParsePatterns(cfg.Models)
+	if err != nil {
+		return nil, fmt.Errorf("notifier %s: %w", n.name, err)
+	}
+	n.models = models
+	for _, event := range cfg.Events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !slices.Contains(diff.Kinds, event) {
+			return nil, fmt.Errorf("notifier %s: unknown event %q (expected %s)", n.name, event, strings.Join(diff.Kinds, ", "))
+		}
+		n.events = append(n.events, event)
+	}
+	text := cfg.Template
+	if text == "" {
+		text = DefaultTemplate
+	}
+	if n.tmpl, err = output.ParseTemplate(n.name, text); err != nil {
+		return nil, fmt.Errorf("notifier %s: %w", n.name, err)
+	}
+	return n, nil
+}
+
+// NewAll builds a notifier for every entry in cfgs.
+func NewAll(cfgs []config.NotifierConfig) ([]*Notifier, error) {
+	notifiers := make([]*Notifier, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		n, err := New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers, nil
+}
+
+func (n *Notifier) Name() string {
+	return n.name
+}
+
+// Notify sends the changes the notifier is subscribed to. It does nothing
+// when none match.
+func (n *Notifier) Notify(ctx context.Context, event Event) error {
+	event.Changes = n.subscribed(event.Changes)
+	if len(event.Changes) == 0 {
+		return nil
+	}
+	var message bytes.Buffer
+	if err := n.tmpl.Execute(&message, event); err != nil {
+		return fmt.Errorf("notifier %s: render message: %w", n.name, err)
+	}
+	target, body, err := n.payload(event, strings.TrimSpace(message.String()))
+	if err != nil {
+		return fmt.Errorf("notifier %s: %w", n.name, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = n.deliver(ctx, target, body)
+		var permanent *permanentError
+		if err == nil || errors.As(err, &permanent) || attempt >= n.retries {
+			break
+		}
+		timer := time.NewTimer(n.backoff << attempt)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("notifier %s: %w", n.name, ctx.Err())
+		case <-timer.C:
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("notifier %s: %w", n.name, err)
+	}
+	return nil
+}
+
+// Send delivers event to every notifier and joins their errors.
+func Send(ctx context.Context, notifiers []*Notifier, event Event) error {
+	var errs []error
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) subscribed(changes []diff.Change) []diff.Change {
+	var kept []diff.Change
+	for _, change := range changes {
+		if len(n.events) > 0 && !slices.Contains(n.events, change.Kind) {
+			continue
+		}
+		if len(n.models) > 0 && !slices.ContainsFunc(n.models, func(p scout.Pattern) bool { return p.Match(change.Model) }) {
+			continue
+		}
+		kept = append(kept, change)
+	}
+	return kept
+}
+
+// permanentError is a delivery failure that retrying will not fix, such as a
+// rejected payload.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func (n *Notifier) deliver(ctx context.Context, target string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.headers {
+		req.Header.Set(key, os.ExpandEnv(value))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return err
+		}
+		return &permanentError{err: err}
+	}
+	if err := checkResponse(n.kind, respBody); err != nil {
+		return &permanentError{err: err}
+	}
+	return nil
+}
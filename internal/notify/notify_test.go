@@ -0,0 +1,230 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/config"
+	"github.com/NERVEbing/model-scout/internal/diff"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+var testEvent = Event{
+	Time: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+	Changes: []diff.Change{
+		{Kind: diff.KindAdded, Platform: "dashscope", Model: "qwen3-max", New: &platform.ProbeResult{Status: "ok", Available: true}},
+		{Kind: diff.KindNowUnavailable, Platform: "dashscope", Model: "qwen-max", Old: &platform.ProbeResult{Status: "ok", Available: true}, New: &platform.ProbeResult{Status: "fail", Reason: "403 Forbidden"}},
+	},
+}
+
+type received struct {
+	path string
+	body map[string]any
+	raw  string
+}
+
+func receiver(t *testing.T, status int, response string) (*httptest.Server, chan received) {
+	t.Helper()
+	requests := make(chan received, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		json.Unmarshal(data, &body)
+		requests <- received{path: r.URL.RequestURI(), body: body, raw: string(data)}
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestNotifyPayloadShapes(t *testing.T) {
+	cases := []struct {
+		kind  string
+		check func(t *testing.T, got received)
+	}{
+		{kind: "webhook", check: func(t *testing.T, got received) {
+			changes, _ := got.body["changes"].([]any)
+			if len(changes) != 2 || got.body["time"] != "2026-10-19T09:00:00Z" || !strings.Contains(got.body["text"].(string), "now-unavailable: dashscope/qwen-max (fail: 403 Forbidden)") {
+				t.Fatalf("unexpected webhook body: %s", got.raw)
+			}
+		}},
+		{kind: "slack", check: func(t *testing.T, got received) {
+			if !strings.HasPrefix(got.body["text"].(string), "model-scout: 2 model change(s)\n- added: dashscope/qwen3-max (ok)") {
+				t.Fatalf("unexpected slack body: %s", got.raw)
+			}
+		}},
+		{kind: "lark", check: func(t *testing.T, got received) {
+			content, _ := got.body["content"].(map[string]any)
+			if got.body["msg_type"] != "text" || !strings.Contains(content["text"].(string), "qwen-max") {
+				t.Fatalf("unexpected feishu body: %s", got.raw)
+			}
+		}},
+		{kind: "dingtalk", check: func(t *testing.T, got received) {
+			text, _ := got.body["text"].(map[string]any)
+			if got.body["msgtype"] != "text" || !strings.Contains(text["content"].(string), "qwen-max") {
+				t.Fatalf("unexpected dingtalk body: %s", got.raw)
+			}
+		}},
+	}
+	for _, tc := range cases {
+		server, requests := receiver(t, http.StatusOK, `{"code":0,"errcode":0}`)
+		n, err := New(config.NotifierConfig{Type: tc.kind, URL: server.URL})
+		if err != nil {
+			t.Fatalf("%s: new: %v", tc.kind, err)
+		}
+		if err := n.Notify(context.Background(), testEvent); err != nil {
+			t.Fatalf("%s: notify: %v", tc.kind, err)
+		}
+		tc.check(t, <-requests)
+	}
+}
+
+func TestNotifySignsRequests(t *testing.T) {
+	now := func() time.Time { return time.UnixMilli(1700000000123) }
+
+	server, requests := receiver(t, http.StatusOK, `{"errcode":0}`)
+	n, err := New(config.NotifierConfig{Type: "dingtalk", URL: server.URL + "/robot/send?access_token=abc", Secret: "SEC123"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	n.now = now
+	if err := n.Notify(context.Background(), testEvent); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	got := <-requests
+	if !strings.Contains(got.path, "access_token=abc") || !strings.Contains(got.path, "timestamp=1700000000123") || !strings.Contains(got.path, "sign=") {
+		t.Fatalf("unexpected dingtalk URL: %s", got.path)
+	}
+
+	server, requests = receiver(t, http.StatusOK, `{"code":0}`)
+	n, err = New(config.NotifierConfig{Type: "feishu", URL: server.URL, Secret: "SEC123"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	n.now = now
+	if err := n.Notify(context.Background(), testEvent); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	got = <-requests
+	if got.body["timestamp"] != "1700000000" || got.body["sign"] != feishuSign("1700000000", "SEC123") {
+		t.Fatalf("unexpected feishu signature: %s", got.raw)
+	}
+}
+
+func TestNotifySubscriptionsAndTemplate(t *testing.T) {
+	server, requests := receiver(t, http.StatusOK, "ok")
+	n, err := New(config.NotifierConfig{
+		Type:     "slack",
+		URL:      server.URL,
+		Models:   []string{"qwen-max", "re:^deepseek"},
+		Events:   []string{"now-unavailable", "removed"},
+		Template: `{{range .Changes}}{{.Model | upper}} is {{.Kind}}{{end}}`,
+	})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := n.Notify(context.Background(), testEvent); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if got := <-requests; got.body["text"] != "QWEN-MAX is now-unavailable" {
+		t.Fatalf("unexpected message: %s", got.raw)
+	}
+
+	// Nothing subscribed: no request at all.
+	if err := n.Notify(context.Background(), Event{Changes: testEvent.Changes[:1]}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	select {
+	case got := <-requests:
+		t.Fatalf("unexpected request: %s", got.raw)
+	default:
+	}
+}
+
+func TestNotifyRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	n, err := New(config.NotifierConfig{Type: "slack", URL: server.URL, RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := n.Notify(context.Background(), testEvent); err != nil {
+		t.Fatalf("expected delivery after retries, got %v", err)
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts.Load())
+	}
+
+	attempts.Store(0)
+	retries := 1
+	n, err = New(config.NotifierConfig{Name: "ops", Type: "slack", URL: server.URL, Retries: &retries, RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := n.Notify(context.Background(), testEvent); err == nil || !strings.Contains(err.Error(), "notifier ops: 503") {
+		t.Fatalf("expected failure after retries, got %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestNotifyDoesNotRetryRejectedPayload(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		io.WriteString(w, `{"errcode":310000,"errmsg":"sign not match"}`)
+	}))
+	defer server.Close()
+
+	n, err := New(config.NotifierConfig{Type: "dingtalk", URL: server.URL, RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := n.Notify(context.Background(), testEvent); err == nil || !strings.Contains(err.Error(), "sign not match") {
+		t.Fatalf("expected rejected payload error, got %v", err)
+	}
+	if attempts.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", attempts.Load())
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	negative := -1
+	cases := []config.NotifierConfig{
+		{URL: "http://example.com"},
+		{Type: "teams", URL: "http://example.com"},
+		{Type: "slack"},
+		{Type: "slack", URL: "http://example.com", Events: []string{"exploded"}},
+		{Type: "slack", URL: "http://example.com", Models: []string{"re:("}},
+		{Type: "slack", URL: "http://example.com", Template: "{{.Missing"},
+		{Type: "slack", URL: "http://example.com", Retries: &negative},
+	}
+	for _, cfg := range cases {
+		if _, err := New(cfg); err == nil {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+
+	t.Setenv("SLACK_WEBHOOK", "http://hooks.example.com/T000")
+	n, err := New(config.NotifierConfig{Type: "slack", URL: "${SLACK_WEBHOOK}"})
+	if err != nil || n.url != "http://hooks.example.com/T000" || n.Name() != "slack" {
+		t.Fatalf("expected expanded URL, got %+v (%v)", n, err)
+	}
+}
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// payload returns the request URL and body in the shape expected by the
+// notifier type.
+func (n *Notifier) payload(event Event, message string) (string, []byte, error) {
+	var body any
+	target := n.url
+	switch n.kind {
+	case TypeSlack:
+		body = map[string]any{"text": message}
+	case TypeFeishu:
+		feishu := map[string]any{
+			"msg_type": "text",
+			"content":  map[string]string{"text": message},
+		}
+		if n.secret != "" {
+			timestamp := strconv.FormatInt(n.now().Unix(), 10)
+			feishu["timestamp"] = timestamp
+			feishu["sign"] = feishuSign(timestamp, n.secret)
+		}
+		body = feishu
+	case TypeDingTalk:
+		body = map[string]any{
+			"msgtype": "text",
+			"text":    map[string]string{"content": message},
+		}
+		if n.secret != "" {
+			signed, err := dingTalkURL(n.url, n.now().UnixMilli(), n.secret)
+			if err != nil {
+				return "", nil, err
+			}
+			target = signed
+		}
+	default:
+		body = struct {
+			Event
+			Text string `json:"text"`
+		}{Event: event, Text: message}
+	}
+	data, err := json.Marshal(body)
+	return target, data, err
+}
+
+// feishuSign implements the Feishu/Lark custom bot signature: the key is
+// "timestamp\nsecret" and the signed message is empty.
+func feishuSign(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// dingTalkURL adds the DingTalk robot signature parameters to webhook.
+func dingTalkURL(webhook string, timestampMS int64, secret string) (string, error) {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return "", err
+	}
+	timestamp := strconv.FormatInt(timestampMS, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// checkResponse detects failures that Feishu and DingTalk report with a
+// 200 status and an error code in the body.
+func checkResponse(kind string, body []byte) error {
+	var result struct {
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	switch kind {
+	case TypeFeishu, TypeDingTalk:
+		if json.Unmarshal(body, &result) != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	if result.Code != nil && *result.Code != 0 {
+		return fmt.Errorf("error code %d: %s", *result.Code, result.Msg)
+	}
+	if result.ErrCode != nil && *result.ErrCode != 0 {
+		return fmt.Errorf("error code %d: %s", *result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+)
+
+func WriteAvailabilityTable(w io.Writer, summary history.Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLATFORM\tMODEL\tAVAILABILITY\tSAMPLES\tLONGEST OUTAGE\tLATENCY\tTREND\tFLAPPING\tLAST")
+	for _, stats := range summary.Models {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			stats.Platform,
+			keyedModel(stats.Model, stats.Key),
+			formatPercent(stats.Availability),
+			stats.Samples,
+			formatOutage(stats),
+			FormatLatency(stats.MeanLatencyMS),
+			formatTrend(stats),
+			formatFlapping(stats),
+			stats.LastStatus,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, availabilitySummary(summary))
+	return err
+}
+
+func WriteAvailabilityMarkdown(w io.Writer, summary history.Summary) error {
+	var b strings.Builder
+	b.WriteString("# model-scout availability report\n\n")
+	b.WriteString(availabilitySummary(summary) + "\n\n")
+	b.WriteString("| Platform | Model | Availability | Samples | Longest outage | Latency | Trend | Flapping | Last |\n")
+	b.WriteString("|---|---|---:|---:|---:|---:|---:|---|---|\n")
+	for _, stats := range summary.Models {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s | %s | %s | %s | %s |\n",
+			markdownCell(stats.Platform),
+			markdownCell(keyedModel(stats.Model, stats.Key)),
+			formatPercent(stats.Availability),
+			stats.Samples,
+			formatOutage(stats),
+			FormatLatency(stats.MeanLatencyMS),
+			formatTrend(stats),
+			formatFlapping(stats),
+			markdownCell(stats.LastStatus),
+		)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func availabilitySummary(summary history.Summary) string {
+	flapping := 0
+	for _, stats := range summary.Models {
+		if stats.Flapping {
+			flapping++
+		}
+	}
+	line := fmt.Sprintf("%d models from %d scans", len(summary.Models), summary.Scans)
+	if !summary.Since.IsZero() {
+		line += fmt.Sprintf(" between %s and %s",
+			summary.Since.In(time.Local).Format(historyTimeLayout),
+			summary.Until.In(time.Local).Format(historyTimeLayout))
+	}
+	return line + fmt.Sprintf(", %d flapping", flapping)
+}
+
+func formatPercent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value)
+}
+
+func formatOutage(stats history.ModelStats) string {
+	if stats.AvailableSamples == stats.Samples {
+		return "-"
+	}
+	outage := (time.Duration(stats.LongestOutageMS) * time.Millisecond).Round(time.Second).String()
+	if stats.OutageOngoing {
+		outage += " (ongoing)"
+	}
+	return outage
+}
+
+func formatTrend(stats history.ModelStats) string {
+	if stats.FirstHalfLatencyMS == 0 || stats.SecondHalfLatencyMS == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.0f%%", stats.LatencyChange)
+}
+
+func formatFlapping(stats history.ModelStats) string {
+	if !stats.Flapping {
+		return "no"
+	}
+	return fmt.Sprintf("yes (%d changes)", stats.Transitions)
+}
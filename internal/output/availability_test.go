@@ -0,0 +1,40 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+)
+
+func TestWriteAvailabilityTable(t *testing.T) {
+	summary := history.Summary{
+		Since: time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		Scans: 8,
+		Models: []history.ModelStats{
+			{Platform: "dashscope", Model: "qwen-max", Samples: 8, AvailableSamples: 8, Availability: 100, MeanLatencyMS: 800, FirstHalfLatencyMS: 700, SecondHalfLatencyMS: 900, LatencyChange: 28.57, LastStatus: "ok"},
+			{Platform: "dashscope", Model: "qwen-plus", Key: "intl", Samples: 8, AvailableSamples: 4, Availability: 50, LongestOutageMS: 90 * 60 * 1000, OutageOngoing: true, Transitions: 5, Flapping: true, LastStatus: "fail"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAvailabilityTable(&buf, summary); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, 2 rows and footer, got %q", buf.String())
+	}
+	if got := strings.Join(strings.Fields(lines[1]), " "); got != "dashscope qwen-max 100.0% 8 - 800ms +29% no ok" {
+		t.Fatalf("unexpected row: %q", got)
+	}
+	if got := strings.Join(strings.Fields(lines[2]), " "); got != "dashscope qwen-plus [intl] 50.0% 8 1h30m0s (ongoing) - - yes (5 changes) fail" {
+		t.Fatalf("unexpected row: %q", got)
+	}
+	if !strings.HasPrefix(lines[3], "2 models from 8 scans between ") || !strings.HasSuffix(lines[3], ", 1 flapping") {
+		t.Fatalf("unexpected footer: %q", lines[3])
+	}
+}
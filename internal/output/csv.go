@@ -0,0 +1,103 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// capabilitySeparator joins capabilities into a single cell. It is neither a
+// comma nor a tab so the cell never needs quoting in either format.
+const capabilitySeparator = ";"
+
+func WriteCSV(w io.Writer, results []platform.ProbeResult) error {
+	writer := csv.NewWriter(w)
+	header, rows := tabularRows(results)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// WriteTSV writes tab-separated values without quoting. Backslashes, tabs and
+// line breaks inside cells are escaped as \\, \t, \n and \r so every record
+// stays on one line.
+func WriteTSV(w io.Writer, results []platform.ProbeResult) error {
+	header, rows := tabularRows(results)
+	for _, record := range append([][]string{header}, rows...) {
+		cells := make([]string, len(record))
+		for i, cell := range record {
+			cells[i] = tsvEscaper.Replace(cell)
+		}
+		if _, err := io.WriteString(w, strings.Join(cells, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// tabularRows flattens results into columns following the field order of
+// platform.ProbeResult, with one trailing "meta.<key>" column per meta key
+// seen in any result, sorted by key. The "key" column is only present when
+// results were probed with several API keys.
+func tabularRows(results []platform.ProbeResult) ([]string, [][]string) {
+	metaKeys := make(map[string]bool)
+	for _, result := range results {
+		for key := range result.Meta {
+			metaKeys[key] = true
+		}
+	}
+	withKeys := hasKeys(results)
+	sortedKeys := slices.Sorted(maps.Keys(metaKeys))
+
+	header := []string{"platform", "model", "status", "available", "latency_ms", "reason", "capabilities"}
+	if withKeys {
+		header = append(header, "key")
+	}
+	for _, key := range sortedKeys {
+		header = append(header, "meta."+key)
+	}
+
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		latency := ""
+		if result.LatencyMS > 0 {
+			latency = strconv.FormatInt(result.LatencyMS, 10)
+		}
+		row := []string{
+			result.Platform,
+			result.Model,
+			result.Status,
+			strconv.FormatBool(result.Available),
+			latency,
+			result.Reason,
+			strings.Join(result.Capabilities, capabilitySeparator),
+		}
+		if withKeys {
+			row = append(row, result.Key)
+		}
+		for _, key := range sortedKeys {
+			row = append(row, result.Meta[key])
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// hasKeys reports whether results were probed with several API keys, in which
+// case formats show each result's key so rows of one model stay distinct.
+func hasKeys(results []platform.ProbeResult) bool {
+	return slices.ContainsFunc(results, func(result platform.ProbeResult) bool {
+		return result.Key != ""
+	})
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+var tabularResults = []platform.ProbeResult{
+	{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, LatencyMS: 320, Capabilities: []string{"chat", "tools"}, Meta: map[string]string{"region": "cn"}},
+	{Platform: "dashscope", Model: "qwen-mini", Status: "fail", Reason: "403 Forbidden: {\"message\":\"no, \"access\"\"}\n\tdetail", Meta: map[string]string{"account": "a1"}},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, tabularResults); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	wantHeader := "platform,model,status,available,latency_ms,reason,capabilities,meta.account,meta.region"
+	if got := strings.Join(records[0], ","); got != wantHeader {
+		t.Fatalf("unexpected header: %s", got)
+	}
+	if got := strings.Join(records[1], "|"); got != "dashscope|qwen-plus|ok|true|320||chat;tools||cn" {
+		t.Fatalf("unexpected first row: %s", got)
+	}
+	if records[2][5] != tabularResults[1].Reason {
+		t.Fatalf("reason did not round-trip: %q", records[2][5])
+	}
+}
+
+func TestWriteCSVKeys(t *testing.T) {
+	results := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Key: "cn", Status: "ok", Available: true},
+		{Platform: "dashscope", Model: "qwen-plus", Key: "intl", Status: "fail"},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, results); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "platform,model,status,available,latency_ms,reason,capabilities,key" || lines[2] != "dashscope,qwen-plus,fail,false,,,,intl" {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTSV(&buf, tabularResults); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buf.String())
+	}
+	cells := strings.Split(lines[2], "\t")
+	if len(cells) != 9 {
+		t.Fatalf("expected 9 cells, got %d: %q", len(cells), lines[2])
+	}
+	if cells[5] != `403 Forbidden: {"message":"no, "access""}\n\tdetail` {
+		t.Fatalf("unexpected escaped reason: %q", cells[5])
+	}
+}
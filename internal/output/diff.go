@@ -0,0 +1,120 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/NERVEbing/model-scout/internal/diff"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func WriteDiffTable(w io.Writer, changes []diff.Change) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHANGE\tPLATFORM\tMODEL\tOLD\tNEW\tREASON")
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			change.Kind,
+			change.Platform,
+			changeModel(change),
+			statusOf(change.Old),
+			statusOf(change.New),
+			orDash(ShortReason(changeReason(change), maxReasonWidth)),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, diffSummary(changes))
+	return err
+}
+
+func WriteDiffMarkdown(w io.Writer, changes []diff.Change) error {
+	var b strings.Builder
+	b.WriteString("# model-scout diff\n\n")
+	b.WriteString(diffSummary(changes) + "\n")
+	for _, kind := range diff.Kinds {
+		var section []diff.Change
+		for _, change := range changes {
+			if change.Kind == kind {
+				section = append(section, change)
+			}
+		}
+		if len(section) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", diffHeading(kind), len(section))
+		b.WriteString("| Platform | Model | Old | New | Reason |\n|---|---|---|---|---|\n")
+		for _, change := range section {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				markdownCell(change.Platform),
+				markdownCell(changeModel(change)),
+				markdownCell(statusOf(change.Old)),
+				markdownCell(statusOf(change.New)),
+				markdownCell(orDash(changeReason(change))),
+			)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func diffSummary(changes []diff.Change) string {
+	counts := diff.Count(changes)
+	parts := make([]string, 0, len(diff.Kinds))
+	for _, kind := range diff.Kinds {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func diffHeading(kind string) string {
+	switch kind {
+	case diff.KindAdded:
+		return "Added"
+	case diff.KindRemoved:
+		return "Removed"
+	case diff.KindNowAvailable:
+		return "Newly available"
+	case diff.KindNowUnavailable:
+		return "Newly unavailable"
+	case diff.KindReasonChanged:
+		return "Changed failure reasons"
+	default:
+		return kind
+	}
+}
+
+// changeModel names the model of a change, with the API key label when
+// several keys were compared.
+func changeModel(change diff.Change) string {
+	return keyedModel(change.Model, change.Key)
+}
+
+// keyedModel shows the key a model was probed with, when several were.
+func keyedModel(model, key string) string {
+	if key == "" {
+		return model
+	}
+	return model + " [" + key + "]"
+}
+
+func statusOf(result *platform.ProbeResult) string {
+	if result == nil {
+		return "-"
+	}
+	return result.Status
+}
+
+// changeReason is the most relevant failure reason for a change: the new one
+// when the model is still present, otherwise the last known one.
+func changeReason(change diff.Change) string {
+	if change.New != nil {
+		return change.New.Reason
+	}
+	if change.Old != nil {
+		return change.Old.Reason
+	}
+	return ""
+}
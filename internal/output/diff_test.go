@@ -0,0 +1,85 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/diff"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+var diffChanges = []diff.Change{
+	{Kind: diff.KindAdded, Platform: "dashscope", Model: "qwen3-max", New: &platform.ProbeResult{Status: "ok", Available: true}},
+	{Kind: diff.KindNowUnavailable, Platform: "dashscope", Model: "qwen-max", Old: &platform.ProbeResult{Status: "ok", Available: true}, New: &platform.ProbeResult{Status: "fail", Reason: "403 Forbidden"}},
+}
+
+func TestWriteDiffTable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDiffTable(&buf, diffChanges); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, 2 rows and footer, got %q", buf.String())
+	}
+	if got := strings.Join(strings.Fields(lines[2]), " "); got != "now-unavailable dashscope qwen-max ok fail 403 Forbidden" {
+		t.Fatalf("unexpected row: %q", got)
+	}
+	if lines[3] != "1 added, 0 removed, 0 now-available, 1 now-unavailable, 0 reason-changed" {
+		t.Fatalf("unexpected summary: %q", lines[3])
+	}
+}
+
+func TestWriteDiffMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDiffMarkdown(&buf, diffChanges); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"## Added (1)",
+		"| dashscope | qwen3-max | - | ok | - |",
+		"## Newly unavailable (1)",
+		"| dashscope | qwen-max | ok | fail | 403 Forbidden |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected markdown to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "## Removed") {
+		t.Fatalf("expected empty sections to be omitted")
+	}
+}
+
+func TestLoadResults(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"scan.json":   `[{"platform":"dashscope","model":"qwen-plus","status":"ok","available":true,"latency_ms":320}]`,
+		"scan.yaml":   "- platform: dashscope\n  model: qwen-plus\n  status: ok\n  available: true\n  latency_ms: 320\n",
+		"scan.ndjson": "{\"platform\":\"dashscope\",\"model\":\"qwen-plus\",\"status\":\"ok\",\"available\":true,\"latency_ms\":320}\n\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		results, err := LoadResults(path)
+		if err != nil {
+			t.Fatalf("load %s: %v", name, err)
+		}
+		if len(results) != 1 || results[0].Model != "qwen-plus" || !results[0].Available || results[0].LatencyMS != 320 {
+			t.Fatalf("load %s: unexpected results %#v", name, results)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.ndjson")
+	if err := os.WriteFile(bad, []byte("{}\nnot json\n"), 0o644); err != nil {
+		t.Fatalf("write bad: %v", err)
+	}
+	if _, err := LoadResults(bad); err == nil || !strings.Contains(err.Error(), "bad.ndjson:2") {
+		t.Fatalf("expected line number in error, got %v", err)
+	}
+}
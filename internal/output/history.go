@@ -0,0 +1,37 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+)
+
+const historyTimeLayout = "2006-01-02 15:04:05"
+
+// WriteHistoryTable writes one row per recorded result, oldest first.
+func WriteHistoryTable(w io.Writer, entries []history.Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tPLATFORM\tMODEL\tSTATUS\tLATENCY\tKEY\tREASON")
+	scans := make(map[string]struct{})
+	for _, entry := range entries {
+		scans[entry.ScanID] = struct{}{}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Time.In(time.Local).Format(historyTimeLayout),
+			entry.Platform,
+			entry.Model,
+			entry.Status,
+			FormatLatency(entry.LatencyMS),
+			orDash(cmp.Or(entry.Key, entry.KeyFingerprint)),
+			orDash(ShortReason(entry.Reason, maxReasonWidth)),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d results from %d scans\n", len(entries), len(scans))
+	return err
+}
@@ -0,0 +1,34 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestWriteHistoryTable(t *testing.T) {
+	at := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	entries := []history.Entry{
+		{Time: at, ScanID: "a", KeyFingerprint: "sha256:0123", ProbeResult: platform.ProbeResult{Platform: "dashscope", Model: "qwen-plus", Status: "ok", LatencyMS: 320}},
+		{Time: at, ScanID: "a", ProbeResult: platform.ProbeResult{Platform: "dashscope", Model: "qwen-max", Status: "fail", Reason: "403 Forbidden"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHistoryTable(&buf, entries); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, 2 rows and footer, got %q", buf.String())
+	}
+	if got := strings.Join(strings.Fields(lines[1])[2:], " "); got != "dashscope qwen-plus ok 320ms sha256:0123 -" {
+		t.Fatalf("unexpected row: %q", lines[1])
+	}
+	if lines[3] != "2 results from 1 scans" {
+		t.Fatalf("unexpected footer: %q", lines[3])
+	}
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+//go:embed report.html
+var htmlReportTemplate string
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"latency": FormatLatency,
+	"statusClass": func(status string) string {
+		switch status {
+		case "ok", "fail", "denied":
+			return "status-" + status
+		default:
+			return "status-other"
+		}
+	},
+}).Parse(htmlReportTemplate))
+
+// WriteHTML renders a single self-contained HTML page: styles are inlined and
+// nothing is loaded from the network.
+func WriteHTML(w io.Writer, results []platform.ProbeResult, opts ReportOptions) error {
+	return htmlReport.Execute(w, buildReport(results, opts))
+}
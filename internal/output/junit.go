@@ -0,0 +1,110 @@
+package output
+
+import (
+	"cmp"
+	"encoding/xml"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit renders each platform as a <testsuite> and each probed model as
+// a <testcase>, named "model [key]" when results were probed with several
+// keys. Unavailable models are failures, except for status "error"
+// (the probe itself could not complete) which is reported as an error.
+func WriteJUnit(w io.Writer, results []platform.ProbeResult) error {
+	byPlatform := make(map[string][]platform.ProbeResult)
+	for _, result := range results {
+		byPlatform[result.Platform] = append(byPlatform[result.Platform], result)
+	}
+
+	suites := junitTestSuites{Name: "model-scout"}
+	var totalMS int64
+	for _, name := range slices.Sorted(maps.Keys(byPlatform)) {
+		group := byPlatform[name]
+		slices.SortStableFunc(group, func(a, b platform.ProbeResult) int {
+			return cmp.Or(cmp.Compare(a.Model, b.Model), cmp.Compare(a.Key, b.Key))
+		})
+
+		suite := junitTestSuite{Name: name, Tests: len(group)}
+		var suiteMS int64
+		for _, result := range group {
+			suiteMS += result.LatencyMS
+			testCase := junitTestCase{Name: keyedModel(result.Model, result.Key), Classname: name, Time: junitSeconds(result.LatencyMS)}
+			if !result.Available {
+				problem := &junitProblem{
+					Message: ShortReason(result.Reason, 200),
+					Type:    result.Status,
+					Text:    result.Reason,
+				}
+				if result.Status == "error" {
+					testCase.Error = problem
+					suite.Errors++
+				} else {
+					testCase.Failure = problem
+					suite.Failures++
+				}
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		suite.Time = junitSeconds(suiteMS)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		totalMS += suiteMS
+		suites.Suites = append(suites.Suites, suite)
+	}
+	suites.Time = junitSeconds(totalMS)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitSeconds(ms int64) string {
+	return strconv.FormatFloat(float64(ms)/1000, 'f', 3, 64)
+}
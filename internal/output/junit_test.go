@@ -0,0 +1,73 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"slices"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	results := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, LatencyMS: 320},
+		{Platform: "dashscope", Model: "qwen-max", Status: "fail", LatencyMS: 150, Reason: "403 Forbidden: no access"},
+		{Platform: "deepseek", Model: "deepseek-chat", Status: "error", LatencyMS: 15000, Reason: "context deadline exceeded"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, results); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var parsed junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("unmarshal junit: %v\n%s", err, buf.String())
+	}
+	if parsed.Tests != 3 || parsed.Failures != 1 || parsed.Errors != 1 || parsed.Time != "15.470" {
+		t.Fatalf("unexpected totals: %+v", parsed)
+	}
+	if len(parsed.Suites) != 2 || parsed.Suites[0].Name != "dashscope" || parsed.Suites[1].Name != "deepseek" {
+		t.Fatalf("unexpected suites: %+v", parsed.Suites)
+	}
+
+	dashscope := parsed.Suites[0]
+	if dashscope.Tests != 2 || dashscope.Failures != 1 || dashscope.Time != "0.470" {
+		t.Fatalf("unexpected dashscope suite: %+v", dashscope)
+	}
+	failed := dashscope.Cases[0]
+	if failed.Name != "qwen-max" || failed.Classname != "dashscope" || failed.Time != "0.150" {
+		t.Fatalf("unexpected failed case: %+v", failed)
+	}
+	if failed.Failure == nil || failed.Failure.Message != "403 Forbidden: no access" || failed.Failure.Type != "fail" {
+		t.Fatalf("unexpected failure: %+v", failed.Failure)
+	}
+	if dashscope.Cases[1].Failure != nil || dashscope.Cases[1].Error != nil {
+		t.Fatalf("expected passing case, got %+v", dashscope.Cases[1])
+	}
+
+	errored := parsed.Suites[1].Cases[0]
+	if errored.Error == nil || errored.Failure != nil || parsed.Suites[1].Errors != 1 {
+		t.Fatalf("expected error case, got %+v", errored)
+	}
+}
+
+func TestWriteJUnitKeys(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, keyedResults); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var parsed junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("unmarshal junit: %v", err)
+	}
+	var names []string
+	for _, testCase := range parsed.Suites[0].Cases {
+		names = append(names, testCase.Name)
+	}
+	want := []string{"qwen-max [cn]", "qwen-max [intl]", "qwen-plus [cn]", "qwen-plus [intl]"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("expected test cases %v, got %v", want, names)
+	}
+}
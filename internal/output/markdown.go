@@ -0,0 +1,97 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func WriteMarkdown(w io.Writer, results []platform.ProbeResult, opts ReportOptions) error {
+	r := buildReport(results, opts)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", r.Title)
+	if r.Generated != "" {
+		fmt.Fprintf(&b, "Generated %s.\n\n", r.Generated)
+	}
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Platform | Models |")
+	for _, status := range r.Statuses {
+		fmt.Fprintf(&b, " %s |", markdownCell(status))
+	}
+	b.WriteString("\n|---|---:|")
+	for range r.Statuses {
+		b.WriteString("---:|")
+	}
+	b.WriteString("\n")
+	for _, p := range r.Platforms {
+		fmt.Fprintf(&b, "| %s | %d |", markdownCell(p.Name), len(p.Results))
+		for _, status := range r.Statuses {
+			fmt.Fprintf(&b, " %d |", p.Counts[status])
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\n%d models, %d unavailable.\n", r.Total, r.Failures)
+
+	for _, p := range r.Platforms {
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownCell(p.Name))
+		if r.Keys {
+			b.WriteString("| Model | Key | Status | Latency | Capabilities |\n|---|---|---|---:|---|\n")
+		} else {
+			b.WriteString("| Model | Status | Latency | Capabilities |\n|---|---|---:|---|\n")
+		}
+		for _, result := range p.Results {
+			badges := make([]string, 0, len(result.Capabilities))
+			for _, capability := range result.Capabilities {
+				badges = append(badges, "`"+markdownCell(capability)+"`")
+			}
+			model := markdownCell(result.Model)
+			if r.Keys {
+				model += " | " + markdownCell(orDash(result.Key))
+			}
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s |\n",
+				model,
+				statusEmoji(result),
+				markdownCell(result.Status),
+				FormatLatency(result.LatencyMS),
+				orDash(strings.Join(badges, " ")),
+			)
+		}
+		if len(p.Failures) > 0 {
+			fmt.Fprintf(&b, "\n<details>\n<summary>Failures (%d)</summary>\n\n", len(p.Failures))
+			for _, result := range p.Failures {
+				fmt.Fprintf(&b, "- **%s** (%s)\n\n", markdownCell(keyedModel(result.Model, result.Key)), markdownCell(result.Status))
+				if reason := strings.TrimSpace(result.Reason); reason != "" {
+					fence := "```"
+					for strings.Contains(reason, fence) {
+						fence += "`"
+					}
+					fmt.Fprintf(&b, "  %s\n  %s\n  %s\n\n", fence, strings.ReplaceAll(reason, "\n", "\n  "), fence)
+				}
+			}
+			b.WriteString("</details>\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownCellEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+func markdownCell(value string) string {
+	return markdownCellEscaper.Replace(value)
+}
+
+func statusEmoji(result platform.ProbeResult) string {
+	if result.Available {
+		return "✅"
+	}
+	if result.Status == "error" {
+		return "⚠️"
+	}
+	return "❌"
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// WriteKeyMatrix writes one row per platform and model and one column per API
+// key, showing which key can reach which model. Failed cells name the error
+// kind, such as forbidden or rate_limited.
+func WriteKeyMatrix(w io.Writer, results []platform.ProbeResult) error {
+	type row struct{ platform, model string }
+	var (
+		rows      []row
+		labels    []string
+		cells     = make(map[row]map[string]platform.ProbeResult)
+		available = make(map[string]int)
+	)
+	for _, result := range results {
+		r := row{result.Platform, result.Model}
+		if cells[r] == nil {
+			cells[r] = make(map[string]platform.ProbeResult)
+			rows = append(rows, r)
+		}
+		label := cmp.Or(result.Key, "-")
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+		cells[r][label] = result
+		if result.Available {
+			available[label]++
+		}
+	}
+	slices.Sort(labels)
+	slices.SortFunc(rows, func(a, b row) int {
+		return cmp.Or(cmp.Compare(a.platform, b.platform), cmp.Compare(a.model, b.model))
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "PLATFORM\tMODEL\t%s\n", strings.Join(labels, "\t"))
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s", r.platform, r.model)
+		for _, label := range labels {
+			fmt.Fprintf(tw, "\t%s", matrixCell(cells[r], label))
+		}
+		fmt.Fprintln(tw)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		parts = append(parts, fmt.Sprintf("%s %d/%d", label, available[label], len(rows)))
+	}
+	_, err := fmt.Fprintf(w, "available per key: %s\n", strings.Join(parts, ", "))
+	return err
+}
+
+func matrixCell(results map[string]platform.ProbeResult, label string) string {
+	result, ok := results[label]
+	if !ok {
+		return "-"
+	}
+	if result.Available {
+		return result.Status + " " + FormatLatency(result.LatencyMS)
+	}
+	return result.Status + " (" + platform.ErrorKind(result) + ")"
+}
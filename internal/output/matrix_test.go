@@ -0,0 +1,39 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// keyedResults probes each model with two keys.
+var keyedResults = []platform.ProbeResult{
+	{Platform: "dashscope", Model: "qwen-max", Key: "intl", Status: "fail", Reason: "403 Forbidden: no access"},
+	{Platform: "dashscope", Model: "qwen-plus", Key: "intl", Status: "ok", Available: true, LatencyMS: 320},
+	{Platform: "dashscope", Model: "qwen-max", Key: "cn", Status: "ok", Available: true, LatencyMS: 800},
+	{Platform: "dashscope", Model: "qwen-plus", Key: "cn", Status: "fail", Reason: "429 Too Many Requests"},
+}
+
+func TestWriteKeyMatrix(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteKeyMatrix(&buf, keyedResults); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{
+		"PLATFORM MODEL cn intl",
+		"dashscope qwen-max ok 800ms fail (forbidden)",
+		"dashscope qwen-plus fail (rate_limited) ok 320ms",
+		"available per key: cn 1/2, intl 1/2",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	for i := range want {
+		if got := strings.Join(strings.Fields(lines[i]), " "); got != want[i] {
+			t.Fatalf("line %d: expected %q, got %q", i, want[i], got)
+		}
+	}
+}
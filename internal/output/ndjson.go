@@ -0,0 +1,15 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func WriteNDJSON(w io.Writer, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
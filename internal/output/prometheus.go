@@ -0,0 +1,111 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+type ScanInfo struct {
+	Platform string
+	Started  time.Time
+	Duration time.Duration
+}
+
+// WritePrometheus writes results in the Prometheus text exposition format,
+// suitable for node_exporter's textfile collector.
+func WritePrometheus(w io.Writer, results []platform.ProbeResult, info ScanInfo) error {
+	sorted := slices.Clone(results)
+	slices.SortStableFunc(sorted, func(a, b platform.ProbeResult) int {
+		return cmp.Or(cmp.Compare(a.Platform, b.Platform), cmp.Compare(a.Model, b.Model), cmp.Compare(a.Key, b.Key))
+	})
+
+	var b strings.Builder
+	WriteMetricHeader(&b, "model_scout_model_available", "gauge", "Whether the model answered the probe (1) or not (0).")
+	for _, result := range sorted {
+		available := 0
+		if result.Available {
+			available = 1
+		}
+		WriteMetricSample(&b, "model_scout_model_available", modelLabels(result), strconv.Itoa(available))
+	}
+
+	WriteMetricHeader(&b, "model_scout_probe_latency_seconds", "gauge", "Round-trip time of the last probe in seconds.")
+	for _, result := range sorted {
+		if result.LatencyMS <= 0 {
+			continue
+		}
+		WriteMetricSample(&b, "model_scout_probe_latency_seconds", modelLabels(result), FormatMetricFloat(float64(result.LatencyMS)/1000))
+	}
+
+	WriteMetricHeader(&b, "model_scout_scan_models", "gauge", "Number of probed models by status in the last scan.")
+	counts := make(map[[2]string]int)
+	for _, result := range sorted {
+		counts[[2]string{result.Platform, result.Status}]++
+	}
+	for _, key := range slices.SortedFunc(maps.Keys(counts), func(a, b [2]string) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	}) {
+		WriteMetricSample(&b, "model_scout_scan_models", [][2]string{{"platform", key[0]}, {"status", key[1]}}, strconv.Itoa(counts[key]))
+	}
+
+	var scanLabels [][2]string
+	if info.Platform != "" {
+		scanLabels = [][2]string{{"platform", info.Platform}}
+	}
+	if info.Duration > 0 {
+		WriteMetricHeader(&b, "model_scout_scan_duration_seconds", "gauge", "Duration of the last scan in seconds.")
+		WriteMetricSample(&b, "model_scout_scan_duration_seconds", scanLabels, FormatMetricFloat(info.Duration.Seconds()))
+	}
+	if !info.Started.IsZero() {
+		WriteMetricHeader(&b, "model_scout_scan_timestamp_seconds", "gauge", "Unix time the last scan started.")
+		WriteMetricSample(&b, "model_scout_scan_timestamp_seconds", scanLabels, strconv.FormatInt(info.Started.Unix(), 10))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func modelLabels(result platform.ProbeResult) [][2]string {
+	labels := [][2]string{{"platform", result.Platform}, {"model", result.Model}}
+	if result.Key != "" {
+		labels = append(labels, [2]string{"key", result.Key})
+	}
+	return labels
+}
+
+// WriteMetricHeader writes the HELP and TYPE lines of a metric family.
+func WriteMetricHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// WriteMetricSample writes one sample with its labels in the given order,
+// escaping label values.
+func WriteMetricSample(b *strings.Builder, name string, labels [][2]string, value string) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteString("{")
+		for i, label := range labels {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(b, "%s=\"%s\"", label[0], labelEscaper.Replace(label[1]))
+		}
+		b.WriteString("}")
+	}
+	b.WriteString(" " + value + "\n")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// FormatMetricFloat formats a sample value in the shortest exact form.
+func FormatMetricFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
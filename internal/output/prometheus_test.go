@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	results := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, LatencyMS: 320},
+		{Platform: "dashscope", Model: `odd"model`, Status: "fail"},
+		{Platform: "dashscope", Model: "qwen-max", Status: "ok", Available: true, LatencyMS: 1500},
+	}
+	info := ScanInfo{
+		Platform: "dashscope",
+		Started:  time.Unix(1760860800, 0),
+		Duration: 2500 * time.Millisecond,
+	}
+
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf, results, info); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := `# HELP model_scout_model_available Whether the model answered the probe (1) or not (0).
+# TYPE model_scout_model_available gauge
+model_scout_model_available{platform="dashscope",model="odd\"model"} 0
+model_scout_model_available{platform="dashscope",model="qwen-max"} 1
+model_scout_model_available{platform="dashscope",model="qwen-plus"} 1
+# HELP model_scout_probe_latency_seconds Round-trip time of the last probe in seconds.
+# TYPE model_scout_probe_latency_seconds gauge
+model_scout_probe_latency_seconds{platform="dashscope",model="qwen-max"} 1.5
+model_scout_probe_latency_seconds{platform="dashscope",model="qwen-plus"} 0.32
+# HELP model_scout_scan_models Number of probed models by status in the last scan.
+# TYPE model_scout_scan_models gauge
+model_scout_scan_models{platform="dashscope",status="fail"} 1
+model_scout_scan_models{platform="dashscope",status="ok"} 2
+# HELP model_scout_scan_duration_seconds Duration of the last scan in seconds.
+# TYPE model_scout_scan_duration_seconds gauge
+model_scout_scan_duration_seconds{platform="dashscope"} 2.5
+# HELP model_scout_scan_timestamp_seconds Unix time the last scan started.
+# TYPE model_scout_scan_timestamp_seconds gauge
+model_scout_scan_timestamp_seconds{platform="dashscope"} 1760860800
+`
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWritePrometheusKeys(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf, keyedResults, ScanInfo{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`model_scout_model_available{platform="dashscope",model="qwen-max",key="cn"} 1`,
+		`model_scout_model_available{platform="dashscope",model="qwen-max",key="intl"} 0`,
+		`model_scout_probe_latency_seconds{platform="dashscope",model="qwen-plus",key="intl"} 0.32`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// LoadResults reads results written with --out json, yaml or ndjson. NDJSON
+// is recognized by a .ndjson or .jsonl extension; anything else is decoded as
+// YAML, which also accepts JSON.
+func LoadResults(path string) ([]platform.ProbeResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []platform.ProbeResult
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+			var result platform.ProbeResult
+			if err := json.Unmarshal(text, &result); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			results = append(results, result)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	default:
+		if err := yaml.Unmarshal(data, &results); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+type ReportOptions struct {
+	Title     string
+	Generated time.Time
+}
+
+type report struct {
+	Title     string
+	Generated string
+	Total     int
+	Statuses  []string
+	Platforms []platformReport
+	Failures  int
+	// Keys is set when results were probed with several API keys.
+	Keys bool
+}
+
+type platformReport struct {
+	Name     string
+	Counts   map[string]int
+	Results  []platform.ProbeResult
+	Failures []platform.ProbeResult
+}
+
+func buildReport(results []platform.ProbeResult, opts ReportOptions) report {
+	title := opts.Title
+	if title == "" {
+		title = "model-scout report"
+	}
+	r := report{Title: title, Total: len(results), Keys: hasKeys(results)}
+	if !opts.Generated.IsZero() {
+		r.Generated = opts.Generated.Format(time.RFC3339)
+	}
+
+	statuses := make(map[string]bool)
+	byPlatform := make(map[string]*platformReport)
+	for _, result := range results {
+		statuses[result.Status] = true
+		p, ok := byPlatform[result.Platform]
+		if !ok {
+			p = &platformReport{Name: result.Platform, Counts: make(map[string]int)}
+			byPlatform[result.Platform] = p
+		}
+		p.Counts[result.Status]++
+		p.Results = append(p.Results, result)
+		if !result.Available {
+			p.Failures = append(p.Failures, result)
+			r.Failures++
+		}
+	}
+	r.Statuses = slices.Sorted(maps.Keys(statuses))
+
+	byModel := func(a, b platform.ProbeResult) int {
+		return cmp.Or(cmp.Compare(a.Model, b.Model), cmp.Compare(a.Key, b.Key))
+	}
+	for _, name := range slices.Sorted(maps.Keys(byPlatform)) {
+		p := byPlatform[name]
+		slices.SortStableFunc(p.Results, byModel)
+		slices.SortStableFunc(p.Failures, byModel)
+		r.Platforms = append(r.Platforms, *p)
+	}
+	return r
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+var reportResults = []platform.ProbeResult{
+	{Platform: "deepseek", Model: "deepseek-chat", Status: "ok", Available: true, LatencyMS: 900, Capabilities: []string{"chat"}},
+	{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, LatencyMS: 320, Capabilities: []string{"chat"}},
+	{Platform: "dashscope", Model: "qwen|mini", Status: "fail", Reason: "403 Forbidden\n<script>alert(1)</script>"},
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	generated := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	if err := WriteMarkdown(&buf, reportResults, ReportOptions{Generated: generated}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# model-scout report",
+		"Generated 2026-10-19T08:00:00Z.",
+		"| Platform | Models | fail | ok |",
+		"| dashscope | 2 | 1 | 1 |",
+		"| deepseek | 1 | 0 | 1 |",
+		"3 models, 1 unavailable.",
+		"| qwen-plus | ✅ ok | 320ms | `chat` |",
+		`| qwen\|mini | ❌ fail | - | - |`,
+		"<summary>Failures (1)</summary>",
+		"  403 Forbidden\n  <script>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected markdown to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "## dashscope") > strings.Index(out, "## deepseek") {
+		t.Fatalf("expected platforms in name order")
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, reportResults, ReportOptions{Title: "Weekly review"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<title>Weekly review</title>",
+		`<td>dashscope</td><td class="num">2</td><td class="num">1</td><td class="num">1</td>`,
+		`<span class="badge">chat</span>`,
+		"<summary>Failures (1)</summary>",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected html to contain %q, got:\n%s", want, out)
+		}
+	}
+	for _, external := range []string{"<script", "<link", "src=", "http://", "https://"} {
+		if strings.Contains(out, external) {
+			t.Fatalf("expected self-contained html, found %q", external)
+		}
+	}
+}
+
+func TestReportKeys(t *testing.T) {
+	var markdown, html bytes.Buffer
+	if err := WriteMarkdown(&markdown, keyedResults, ReportOptions{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := WriteHTML(&html, keyedResults, ReportOptions{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"| Model | Key | Status | Latency | Capabilities |",
+		"| qwen-max | cn | ✅ ok | 800ms | - |",
+		"- **qwen-max [intl]** (fail)",
+	} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Fatalf("expected markdown to contain %q, got:\n%s", want, markdown.String())
+		}
+	}
+	for _, want := range []string{
+		"<th>Model</th><th>Key</th>",
+		"<td>qwen-max</td><td>intl</td>",
+		"<h3>qwen-max [intl] <span",
+	} {
+		if !strings.Contains(html.String(), want) {
+			t.Fatalf("expected html to contain %q, got:\n%s", want, html.String())
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+const maxReasonWidth = 60
+
+type TableOptions struct {
+	// Color wraps statuses in ANSI colors. Only enable it for terminals.
+	Color bool
+}
+
+func WriteTable(w io.Writer, results []platform.ProbeResult, opts TableOptions) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	withKeys := hasKeys(results)
+	if withKeys {
+		fmt.Fprintln(tw, "PLATFORM\tMODEL\tKEY\tSTATUS\tLATENCY\tCAPABILITIES\tREASON")
+	} else {
+		fmt.Fprintln(tw, "PLATFORM\tMODEL\tSTATUS\tLATENCY\tCAPABILITIES\tREASON")
+	}
+	for _, result := range results {
+		status := result.Status
+		if opts.Color {
+			status = colorStatus(status)
+		}
+		model := result.Model
+		if withKeys {
+			model += "\t" + orDash(result.Key)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			result.Platform,
+			model,
+			status,
+			FormatLatency(result.LatencyMS),
+			orDash(strings.Join(result.Capabilities, ",")),
+			orDash(ShortReason(result.Reason, maxReasonWidth)),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, summaryLine(results))
+	return err
+}
+
+// CountByStatus returns the number of results for each status.
+func CountByStatus(results []platform.ProbeResult) map[string]int {
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+	}
+	return counts
+}
+
+func summaryLine(results []platform.ProbeResult) string {
+	counts := CountByStatus(results)
+	parts := make([]string, 0, len(counts))
+	for _, status := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	line := fmt.Sprintf("%d models", len(results))
+	if len(parts) > 0 {
+		line += ": " + strings.Join(parts, ", ")
+	}
+	return line
+}
+
+func FormatLatency(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// ShortReason returns the first line of reason, truncated to width runes.
+func ShortReason(reason string, width int) string {
+	reason, _, _ = strings.Cut(strings.TrimSpace(reason), "\n")
+	reason = strings.TrimSpace(reason)
+	runes := []rune(reason)
+	if len(runes) <= width {
+		return reason
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// colorStatus uses escape sequences of identical length for every status so
+// tabwriter keeps the columns aligned.
+func colorStatus(status string) string {
+	code := "33"
+	switch status {
+	case "ok":
+		code = "32"
+	case "fail", "denied":
+		code = "31"
+	}
+	return "\x1b[" + code + "m" + status + "\x1b[0m"
+}
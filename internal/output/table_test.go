@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestWriteTable(t *testing.T) {
+	results := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, LatencyMS: 320, Capabilities: []string{"chat"}},
+		{Platform: "dashscope", Model: "qwen-mini", Status: "fail", LatencyMS: 1500, Reason: "403 Forbidden: no access\n{\"code\":\"AccessDenied\"}"},
+		{Platform: "dashscope", Model: "qwen-max", Status: "ok", Available: true, LatencyMS: 800, Capabilities: []string{"chat"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, results, TableOptions{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header, 3 rows and footer, got %q", buf.String())
+	}
+	if got := strings.Fields(lines[2]); strings.Join(got, " ") != "dashscope qwen-mini fail 1.5s - 403 Forbidden: no access" {
+		t.Fatalf("unexpected row: %q", lines[2])
+	}
+	if strings.Index(lines[0], "STATUS") != strings.Index(lines[1], "ok") {
+		t.Fatalf("columns not aligned:\n%s", buf.String())
+	}
+	if lines[4] != "3 models: 1 fail, 2 ok" {
+		t.Fatalf("unexpected footer: %q", lines[4])
+	}
+}
+
+func TestWriteTableColor(t *testing.T) {
+	results := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Status: "ok"},
+		{Platform: "dashscope", Model: "qwen-mini", Status: "fail"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, results, TableOptions{Color: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "\x1b[32mok\x1b[0m") || !strings.Contains(buf.String(), "\x1b[31mfail\x1b[0m") {
+		t.Fatalf("expected colored statuses, got %q", buf.String())
+	}
+}
+
+func TestShortReason(t *testing.T) {
+	if got := ShortReason("  first line\nsecond", 20); got != "first line" {
+		t.Fatalf("unexpected reason: %q", got)
+	}
+	if got := ShortReason("abcdefghij", 5); got != "abcd…" {
+		t.Fatalf("unexpected truncation: %q", got)
+	}
+}
+
+func TestWriteTableKeys(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, keyedResults[:2], TableOptions{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got := strings.Join(strings.Fields(lines[0]), " "); got != "PLATFORM MODEL KEY STATUS LATENCY CAPABILITIES REASON" {
+		t.Fatalf("unexpected header: %q", got)
+	}
+	if got := strings.Join(strings.Fields(lines[2]), " "); got != "dashscope qwen-plus intl ok 320ms - -" {
+		t.Fatalf("unexpected row: %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// templateFuncs are available to user templates in addition to the
+// text/template builtins. Like the builtins, they take the value being
+// operated on last so they work in pipelines: {{.Capabilities | join ","}}.
+// groupBy and countBy take a field name accepted by ResultField.
+var templateFuncs = template.FuncMap{
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"toJSON": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"groupBy": func(key string, results []platform.ProbeResult) (map[string][]platform.ProbeResult, error) {
+		groups := make(map[string][]platform.ProbeResult)
+		for _, result := range results {
+			value, err := ResultField(result, key)
+			if err != nil {
+				return nil, err
+			}
+			groups[value] = append(groups[value], result)
+		}
+		return groups, nil
+	},
+	"countBy": func(key string, results []platform.ProbeResult) (map[string]int, error) {
+		counts := make(map[string]int)
+		for _, result := range results {
+			value, err := ResultField(result, key)
+			if err != nil {
+				return nil, err
+			}
+			counts[value]++
+		}
+		return counts, nil
+	},
+}
+
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
+
+// WriteTemplate executes tmpl with the result slice as dot.
+func WriteTemplate(w io.Writer, tmpl *template.Template, results []platform.ProbeResult) error {
+	return tmpl.Execute(w, results)
+}
+
+// ResultField returns a result field by its JSON name, or a meta value for
+// "meta.<key>".
+func ResultField(result platform.ProbeResult, key string) (string, error) {
+	if metaKey, ok := strings.CutPrefix(key, "meta."); ok {
+		return result.Meta[metaKey], nil
+	}
+	switch key {
+	case "platform":
+		return result.Platform, nil
+	case "model":
+		return result.Model, nil
+	case "status":
+		return result.Status, nil
+	case "available":
+		return strconv.FormatBool(result.Available), nil
+	case "latency_ms":
+		return strconv.FormatInt(result.LatencyMS, 10), nil
+	case "reason":
+		return result.Reason, nil
+	case "key":
+		return result.Key, nil
+	default:
+		return "", fmt.Errorf("unknown result field %q", key)
+	}
+}
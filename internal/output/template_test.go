@@ -0,0 +1,66 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	results := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, Capabilities: []string{"chat", "tools"}},
+		{Platform: "dashscope", Model: "qwen-mini", Status: "fail"},
+		{Platform: "deepseek", Model: "deepseek-chat", Status: "ok", Available: true},
+	}
+	text := `{{range $platform, $group := groupBy "platform" .}}{{upper $platform}}={{range $group}}{{.Model}};{{end}}
+{{end}}{{range $status, $n := countBy "status" .}}{{$status}}:{{$n}} {{end}}
+{{(index . 0).Capabilities | join "|"}} {{toJSON (index . 1).Model}}
+`
+	tmpl, err := ParseTemplate("test", text)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, tmpl, results); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := "DASHSCOPE=qwen-plus;qwen-mini;\nDEEPSEEK=deepseek-chat;\nfail:1 ok:2 \nchat|tools \"qwen-mini\"\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestWriteTemplateGroupByKey(t *testing.T) {
+	results := []platform.ProbeResult{
+		{Platform: "dashscope", Model: "qwen-plus", Key: "cn", Status: "ok", Available: true, LatencyMS: 120},
+		{Platform: "dashscope", Model: "qwen-plus", Key: "intl", Status: "denied"},
+		{Platform: "dashscope", Model: "qwen-max", Key: "cn", Status: "ok", Available: true, LatencyMS: 340},
+	}
+	tmpl, err := ParseTemplate("test", `{{range $key, $group := groupBy "key" .}}{{$key}}:{{range $group}} {{.Model}}{{end}}
+{{end}}{{countBy "latency_ms" .}}`)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, tmpl, results); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := "cn: qwen-plus qwen-max\nintl: qwen-plus\nmap[0:1 120:1 340:1]"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestWriteTemplateUnknownField(t *testing.T) {
+	tmpl, err := ParseTemplate("test", `{{countBy "size" .}}`)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, tmpl, []platform.ProbeResult{{Model: "m"}}); err == nil {
+		t.Fatalf("expected error for unknown field")
+	}
+}
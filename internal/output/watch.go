@@ -0,0 +1,29 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/diff"
+)
+
+// WriteChangeLog writes one line per change detected at the given time, for
+// following a long-running watch in a terminal or log file.
+func WriteChangeLog(w io.Writer, at time.Time, changes []diff.Change) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	stamp := at.In(time.Local).Format(historyTimeLayout)
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s -> %s\t%s\n",
+			stamp,
+			change.Kind,
+			change.Platform,
+			change.Model,
+			statusOf(change.Old),
+			statusOf(change.New),
+			orDash(ShortReason(changeReason(change), maxReasonWidth)),
+		)
+	}
+	return tw.Flush()
+}
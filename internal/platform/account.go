@@ -0,0 +1,68 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// Account problems reported by ClassifyAccountError.
+const (
+	AccountInvalidKey    = "invalid_key"
+	AccountOverdue       = "overdue_balance"
+	AccountRegionBlocked = "region_blocked"
+	AccountForbidden     = "forbidden"
+	AccountRateLimited   = "rate_limited"
+	AccountServer        = "server_error"
+	AccountTimeout       = "timeout"
+	AccountNetwork       = "network"
+	AccountOther         = "other"
+)
+
+// AccountProblem explains why a key could not be used.
+type AccountProblem struct {
+	Kind string `json:"kind" yaml:"kind"`
+	Hint string `json:"hint" yaml:"hint"`
+}
+
+// ClassifyAccountError classifies an error from a call made with a key, such
+// as ListModels, into an account-level problem with a hint on how to fix it.
+func ClassifyAccountError(err error) AccountProblem {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		body := strings.ToLower(httpErr.Body)
+		switch {
+		case httpErr.StatusCode == 402 || containsAny(body, "arrearage", "overdue", "insufficient balance", "insufficient_balance", "insufficient_quota"):
+			return AccountProblem{AccountOverdue, "the account is out of credit or has an overdue bill; top up its balance in the provider console"}
+		case httpErr.StatusCode == 401 || containsAny(body, "invalid_api_key", "invalid api key", "incorrect api key"):
+			return AccountProblem{AccountInvalidKey, "the key was rejected; check that it is complete, not revoked, and issued for this platform and region"}
+		case httpErr.StatusCode == 403 && containsAny(body, "region", "country", "territory", "location"):
+			return AccountProblem{AccountRegionBlocked, "the service is not available from this network location; use the endpoint for your region or an egress proxy in an allowed region"}
+		case httpErr.StatusCode == 403:
+			return AccountProblem{AccountForbidden, "the key is valid but not allowed to call this API; check its permissions or workspace"}
+		case httpErr.StatusCode == 429:
+			return AccountProblem{AccountRateLimited, "the key is rate limited; retry later or lower --workers"}
+		case httpErr.StatusCode >= 500:
+			return AccountProblem{AccountServer, "the platform failed to answer; check its status page and retry later"}
+		}
+		return AccountProblem{AccountOther, "the platform rejected the request; see the response for details"}
+	}
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return AccountProblem{AccountTimeout, "the platform did not answer in time; check the network path or raise --timeout"}
+	case errors.As(err, &netErr):
+		return AccountProblem{AccountNetwork, "the platform could not be reached; check DNS, firewalls and proxy settings"}
+	}
+	return AccountProblem{AccountOther, "see the error for details"}
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestClassifyAccountError(t *testing.T) {
+	httpErr := func(code int, body string) error {
+		return fmt.Errorf("scan: %w", &HTTPError{Op: "dashscope list models", StatusCode: code, Status: fmt.Sprintf("%d", code), Body: body})
+	}
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{httpErr(401, `{"error":{"code":"invalid_api_key"}}`), AccountInvalidKey},
+		{httpErr(400, `{"code":"Arrearage","message":"Access denied, please make sure your account is in good standing."}`), AccountOverdue},
+		{httpErr(402, `{"error":{"message":"Insufficient Balance"}}`), AccountOverdue},
+		{httpErr(403, `{"error":{"code":"unsupported_country_region_territory"}}`), AccountRegionBlocked},
+		{httpErr(403, `{"code":"AccessDenied"}`), AccountForbidden},
+		{httpErr(429, ""), AccountRateLimited},
+		{httpErr(503, ""), AccountServer},
+		{httpErr(400, "bad"), AccountOther},
+		{fmt.Errorf("list: %w", context.DeadlineExceeded), AccountTimeout},
+		{&net.DNSError{Err: "no such host", Name: "example.invalid"}, AccountNetwork},
+		{errors.New("decode: unexpected EOF"), AccountOther},
+	}
+	for _, tt := range tests {
+		got := ClassifyAccountError(tt.err)
+		if got.Kind != tt.want || got.Hint == "" {
+			t.Fatalf("%v: expected %s, got %+v", tt.err, tt.want, got)
+		}
+	}
+}
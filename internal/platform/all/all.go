@@ -0,0 +1,7 @@
+// Package all registers every built-in platform driver when imported.
+package all
+
+import (
+	_ "github.com/NERVEbing/model-scout/internal/platform/dashscope"
+	_ "github.com/NERVEbing/model-scout/internal/platform/deepseek"
+)
@@ -5,6 +5,8 @@ import (
 	"time"
 )
 
+const DefaultBaseURL = "https://dashscope.aliyuncs.com/compatible-mode/v1"
+
 type Client struct {
 	BaseURL    string
 	APIKey     string
@@ -13,7 +15,7 @@ type Client struct {
 
 func NewClient(apiKey string, timeout time.Duration) *Client {
 	return &Client{
-		BaseURL: "https://dashscope.aliyuncs.com/compatible-mode/v1",
+		BaseURL: DefaultBaseURL,
 		APIKey:  apiKey,
 		HTTPClient: &http.Client{
 			Timeout: timeout,
@@ -1,6 +1,41 @@
 package dashscope
 
-import "time"
+import (
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+var defaultExcludes = []string{
+	"image",
+	"imageedit",
+	"tts",
+	"asr",
+	"mt",
+	"ocr",
+	"rerank",
+	"embedding",
+	"realtime",
+	"livetranslate",
+}
+
+func init() {
+	platform.Register(platform.Descriptor{
+		Name:       "dashscope",
+		Aliases:    []string{"bailian"},
+		KeyEnv:     []string{"DASHSCOPE_API_KEY"},
+		BaseURL:    DefaultBaseURL,
+		Auth:       platform.AuthBearer,
+		ProbeKinds: []string{"chat"},
+		New: func(opts platform.Options) (platform.Platform, error) {
+			p := NewPlatform(opts.APIKey, opts.Timeout)
+			if opts.HTTPClient != nil {
+				p.client.HTTPClient = opts.HTTPClient
+			}
+			return p, nil
+		},
+	})
+}
 
 type Platform struct {
 	client *Client
@@ -13,3 +48,7 @@ func NewPlatform(apiKey string, timeout time.Duration) *Platform {
 func (p *Platform) Name() string {
 	return "dashscope"
 }
+
+func (p *Platform) DefaultExcludes() []string {
+	return defaultExcludes
+}
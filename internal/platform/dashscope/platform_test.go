@@ -0,0 +1,43 @@
+package dashscope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/scout"
+)
+
+func TestDefaultExcludes(t *testing.T) {
+	// The substring list these defaults replaced; token matching must still
+	// skip every real DashScope model it used to skip.
+	baseline := []string{"image", "tts", "asr", "mt", "ocr", "rerank", "embedding", "realtime", "livetranslate"}
+	selector, err := scout.NewSelector(nil, nil, (&Platform{}).DefaultExcludes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{
+		"qwen-max", "qwen-plus-latest", "qwen3-max", "qwen-turbo", "qwen-long", "qwq-plus",
+		"qwen-vl-max", "qwen2.5-vl-72b-instruct", "qwen3-coder-plus", "qwen-omni-turbo", "qwen-math-turbo",
+		"deepseek-v3", "deepseek-r1", "wanx2.1-t2i-turbo", "cosyvoice-v2",
+		"qwen-image", "qwen-image-edit", "qwen-image-plus", "wanx2.1-imageedit", "wanx-sketch-to-image-lite",
+		"qwen-tts", "qwen3-tts-flash", "qwen-tts-realtime", "qwen-audio-asr", "qwen3-asr-flash",
+		"qwen-mt-turbo", "qwen-mt-plus", "qwen-vl-ocr", "qwen-vl-ocr-latest", "gte-rerank-v2",
+		"text-embedding-v3", "text-embedding-async-v2", "multimodal-embedding-v1",
+		"paraformer-realtime-v2", "paraformer-realtime-8k-v2", "qwen-omni-turbo-realtime", "gummy-realtime-v1",
+		"qwen3-livetranslate-flash-realtime",
+	} {
+		want := !containsAny(id, baseline)
+		if got := selector.Decide(id); got.Selected != want {
+			t.Errorf("%s: selected %v, want %v (%s %s)", id, got.Selected, want, got.Rule, got.Pattern)
+		}
+	}
+}
+
+func containsAny(id string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(id, substring) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 )
 
+const DefaultBaseURL = "https://api.deepseek.com/v1"
+
 type Client struct {
 	BaseURL    string
 	APIKey     string
@@ -13,7 +15,7 @@ type Client struct {
 
 func NewClient(apiKey string, timeout time.Duration) *Client {
 	return &Client{
-		BaseURL: "https://api.deepseek.com/v1",
+		BaseURL: DefaultBaseURL,
 		APIKey:  apiKey,
 		HTTPClient: &http.Client{
 			Timeout: timeout,
@@ -35,11 +35,12 @@ func (p *Platform) ListModels(ctx context.Context) ([]platform.Model, error) {
 		if err != nil {
 			return nil, fmt.Errorf("deepseek list models failed: %s (read body: %v)", resp.Status, err)
 		}
-		reason := strings.TrimSpace(string(body))
-		if reason != "" {
-			return nil, fmt.Errorf("deepseek list models failed: %s: %s", resp.Status, reason)
+		return nil, &platform.HTTPError{
+			Op:         "deepseek list models",
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       strings.TrimSpace(string(body)),
 		}
-		return nil, fmt.Errorf("deepseek list models failed: %s", resp.Status)
 	}
 
 	var payload listResponse
@@ -1,6 +1,27 @@
 package deepseek
 
-import "time"
+import (
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+func init() {
+	platform.Register(platform.Descriptor{
+		Name:       "deepseek",
+		KeyEnv:     []string{"DEEPSEEK_API_KEY"},
+		BaseURL:    DefaultBaseURL,
+		Auth:       platform.AuthBearer,
+		ProbeKinds: []string{"chat"},
+		New: func(opts platform.Options) (platform.Platform, error) {
+			p := NewPlatform(opts.APIKey, opts.Timeout)
+			if opts.HTTPClient != nil {
+				p.client.HTTPClient = opts.HTTPClient
+			}
+			return p, nil
+		},
+	})
+}
 
 type Platform struct {
 	client *Client
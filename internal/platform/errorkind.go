@@ -0,0 +1,82 @@
+package platform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error kinds reported by ErrorKind.
+const (
+	ErrorUnauthorized = "unauthorized"
+	ErrorForbidden    = "forbidden"
+	ErrorNotFound     = "not_found"
+	ErrorRateLimited  = "rate_limited"
+	ErrorBadRequest   = "bad_request"
+	ErrorServer       = "server_error"
+	ErrorTimeout      = "timeout"
+	ErrorNetwork      = "network"
+	ErrorOther        = "other"
+)
+
+// ErrorKind classifies why a probe failed, from the HTTP status at the start
+// of a "fail" reason or the transport error of an "error" result. It returns
+// "" for available results.
+func ErrorKind(result ProbeResult) string {
+	if result.Available {
+		return ""
+	}
+	if code, ok := statusCode(result.Reason); ok {
+		switch {
+		case code == 401:
+			return ErrorUnauthorized
+		case code == 403:
+			return ErrorForbidden
+		case code == 404:
+			return ErrorNotFound
+		case code == 429:
+			return ErrorRateLimited
+		case code >= 500:
+			return ErrorServer
+		case code >= 400:
+			return ErrorBadRequest
+		}
+	}
+	reason := strings.ToLower(result.Reason)
+	switch {
+	case strings.Contains(reason, "deadline exceeded"), strings.Contains(reason, "timeout"):
+		return ErrorTimeout
+	case result.Status == "error":
+		return ErrorNetwork
+	default:
+		return ErrorOther
+	}
+}
+
+// statusCode parses a leading HTTP status such as "403 Forbidden: ...".
+func statusCode(reason string) (int, bool) {
+	if len(reason) < 3 || (len(reason) > 3 && reason[3] != ' ' && reason[3] != ':') {
+		return 0, false
+	}
+	code, err := strconv.Atoi(reason[:3])
+	if err != nil || code < 100 || code > 599 {
+		return 0, false
+	}
+	return code, true
+}
+
+// HTTPError reports a platform API call answered with an unexpected status.
+type HTTPError struct {
+	// Op names the call, such as "dashscope list models".
+	Op         string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("%s failed: %s: %s", e.Op, e.Status, e.Body)
+	}
+	return fmt.Sprintf("%s failed: %s", e.Op, e.Status)
+}
@@ -0,0 +1,37 @@
+package platform
+
+import "testing"
+
+func TestErrorKind(t *testing.T) {
+	cases := []struct {
+		result ProbeResult
+		want   string
+	}{
+		{result: ProbeResult{Status: "ok", Available: true}, want: ""},
+		{result: ProbeResult{Status: "fail", Reason: "401 Unauthorized"}, want: ErrorUnauthorized},
+		{result: ProbeResult{Status: "fail", Reason: `403 Forbidden: {"code":"AccessDenied"}`}, want: ErrorForbidden},
+		{result: ProbeResult{Status: "fail", Reason: "404 Not Found"}, want: ErrorNotFound},
+		{result: ProbeResult{Status: "fail", Reason: "429 Too Many Requests"}, want: ErrorRateLimited},
+		{result: ProbeResult{Status: "fail", Reason: "400 Bad Request: model does not support chat"}, want: ErrorBadRequest},
+		{result: ProbeResult{Status: "fail", Reason: "503 Service Unavailable"}, want: ErrorServer},
+		{result: ProbeResult{Status: "error", Reason: `Post "https://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)`}, want: ErrorTimeout},
+		{result: ProbeResult{Status: "error", Reason: "dial tcp: lookup example.com: no such host"}, want: ErrorNetwork},
+		{result: ProbeResult{Status: "fail", Reason: "1000 tokens is too many"}, want: ErrorOther},
+	}
+	for _, tc := range cases {
+		if got := ErrorKind(tc.result); got != tc.want {
+			t.Fatalf("ErrorKind(%q): expected %q, got %q", tc.result.Reason, tc.want, got)
+		}
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	err := &HTTPError{Op: "deepseek list models", StatusCode: 401, Status: "401 Unauthorized", Body: "bad key"}
+	if got := err.Error(); got != "deepseek list models failed: 401 Unauthorized: bad key" {
+		t.Fatalf("unexpected message: %q", got)
+	}
+	err.Body = ""
+	if got := err.Error(); got != "deepseek list models failed: 401 Unauthorized" {
+		t.Fatalf("unexpected message: %q", got)
+	}
+}
@@ -1,6 +1,10 @@
 package platform
 
-import "context"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
 
 type Platform interface {
 	Name() string
@@ -8,6 +12,20 @@ type Platform interface {
 	Probe(ctx context.Context, model Model) ProbeResult
 }
 
+// DefaultExcluder is implemented by platforms whose catalogs list models that
+// cannot answer a chat probe (image, speech, embedding, ...). The patterns use
+// the same syntax as --exclude.
+type DefaultExcluder interface {
+	DefaultExcludes() []string
+}
+
+func DefaultExcludes(p Platform) []string {
+	if excluder, ok := p.(DefaultExcluder); ok {
+		return excluder.DefaultExcludes()
+	}
+	return nil
+}
+
 type Model struct {
 	ID   string
 	Meta map[string]string
@@ -18,7 +36,18 @@ type ProbeResult struct {
 	Model        string            `json:"model" yaml:"model"`
 	Status       string            `json:"status" yaml:"status"`
 	Available    bool              `json:"available" yaml:"available"`
+	LatencyMS    int64             `json:"latency_ms,omitempty" yaml:"latency_ms,omitempty"`
 	Reason       string            `json:"reason,omitempty" yaml:"reason,omitempty"`
 	Capabilities []string          `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
 	Meta         map[string]string `json:"meta,omitempty" yaml:"meta,omitempty"`
+	// Key identifies the API key that made the probe, by label or
+	// fingerprint, when a platform is probed with several keys.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// KeyFingerprint identifies an API key without revealing it, so results and
+// history can be attributed to a key safely.
+func KeyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:6])
 }
@@ -0,0 +1,115 @@
+package platform
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthBearer sends the API key as "Authorization: Bearer <key>".
+const AuthBearer = "bearer"
+
+// Options configure a platform created from its descriptor.
+type Options struct {
+	APIKey  string
+	Timeout time.Duration
+	// HTTPClient, if set, replaces the platform's default client, for
+	// example one built with NewHTTPClient.
+	HTTPClient *http.Client
+}
+
+// Descriptor describes a platform to the registry. Platform packages
+// register one from init.
+type Descriptor struct {
+	Name    string
+	Aliases []string
+	// KeyEnv lists the environment variables an API key is read from, in
+	// order of precedence.
+	KeyEnv  []string
+	BaseURL string
+	// Auth is how the key is sent, such as AuthBearer.
+	Auth string
+	// ProbeKinds lists the capabilities a probe checks, such as "chat".
+	ProbeKinds []string
+	New        func(Options) (Platform, error)
+}
+
+// EnvKey returns the first non-empty API key found in d.KeyEnv and the
+// variable it came from.
+func (d Descriptor) EnvKey() (key, env string) {
+	for _, env := range d.KeyEnv {
+		if key := strings.TrimSpace(os.Getenv(env)); key != "" {
+			return key, env
+		}
+	}
+	return "", ""
+}
+
+var registry struct {
+	sync.RWMutex
+	descriptors []Descriptor
+	names       map[string]int
+}
+
+// Register adds a platform to the registry. It panics if the descriptor is
+// incomplete or a name or alias is already taken.
+func Register(d Descriptor) {
+	if d.Name == "" || d.New == nil {
+		panic("platform: Register requires a name and a constructor")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.names == nil {
+		registry.names = make(map[string]int)
+	}
+	names := append([]string{d.Name}, d.Aliases...)
+	for _, name := range names {
+		if _, ok := registry.names[strings.ToLower(name)]; ok {
+			panic("platform: " + name + " is already registered")
+		}
+	}
+	for _, name := range names {
+		registry.names[strings.ToLower(name)] = len(registry.descriptors)
+	}
+	registry.descriptors = append(registry.descriptors, d)
+}
+
+// Lookup finds a platform by name or alias, ignoring case.
+func Lookup(name string) (Descriptor, error) {
+	registry.RLock()
+	i, ok := registry.names[strings.ToLower(strings.TrimSpace(name))]
+	var d Descriptor
+	if ok {
+		d = registry.descriptors[i]
+	}
+	registry.RUnlock()
+	if !ok {
+		return Descriptor{}, fmt.Errorf("unsupported platform: %s (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+	return d, nil
+}
+
+// Descriptors returns every registered platform, sorted by name.
+func Descriptors() []Descriptor {
+	registry.RLock()
+	descriptors := slices.Clone(registry.descriptors)
+	registry.RUnlock()
+	slices.SortFunc(descriptors, func(a, b Descriptor) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return descriptors
+}
+
+// Names returns the names of every registered platform, sorted.
+func Names() []string {
+	var names []string
+	for _, d := range Descriptors() {
+		names = append(names, d.Name)
+	}
+	return names
+}
@@ -0,0 +1,50 @@
+package platform
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type stubPlatform struct{}
+
+func (stubPlatform) Name() string                                { return "stub" }
+func (stubPlatform) ListModels(context.Context) ([]Model, error) { return nil, nil }
+func (stubPlatform) Probe(context.Context, Model) ProbeResult    { return ProbeResult{} }
+
+func TestRegistry(t *testing.T) {
+	Register(Descriptor{
+		Name:    "registry-test",
+		Aliases: []string{"registry-alias"},
+		KeyEnv:  []string{"REGISTRY_TEST_KEY", "REGISTRY_TEST_FALLBACK"},
+		New:     func(Options) (Platform, error) { return stubPlatform{}, nil },
+	})
+
+	for _, name := range []string{"registry-test", "Registry-Alias"} {
+		d, err := Lookup(name)
+		if err != nil || d.Name != "registry-test" {
+			t.Fatalf("lookup %s: got %+v, %v", name, d, err)
+		}
+	}
+	if _, err := Lookup("missing"); err == nil || !strings.Contains(err.Error(), "registry-test") {
+		t.Fatalf("expected an error listing the supported platforms, got %v", err)
+	}
+	if !slices.Contains(Names(), "registry-test") || slices.Contains(Names(), "registry-alias") {
+		t.Fatalf("unexpected names: %v", Names())
+	}
+
+	d, _ := Lookup("registry-test")
+	t.Setenv("REGISTRY_TEST_KEY", "")
+	t.Setenv("REGISTRY_TEST_FALLBACK", " sk-fallback ")
+	if key, env := d.EnvKey(); key != "sk-fallback" || env != "REGISTRY_TEST_FALLBACK" {
+		t.Fatalf("expected the fallback key, got %q from %q", key, env)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a duplicate alias to panic")
+		}
+	}()
+	Register(Descriptor{Name: "other", Aliases: []string{"registry-alias"}, New: func(Options) (Platform, error) { return stubPlatform{}, nil }})
+}
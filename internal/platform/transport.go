@@ -0,0 +1,84 @@
+package platform
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ProxyDirect disables proxying, including the proxy environment variables.
+const ProxyDirect = "direct"
+
+// TransportOptions configure how a platform connects. The zero value uses
+// the proxy environment variables and the system certificate pool.
+type TransportOptions struct {
+	// Proxy is an http, https, socks5 or socks5h URL, or ProxyDirect. Empty
+	// uses HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+	Proxy string
+	// CAFile is a PEM bundle trusted in addition to the system pool.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and key for mTLS.
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+}
+
+// NewHTTPClient returns a client whose transport applies opts.
+func NewHTTPClient(timeout time.Duration, opts TransportOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch opts.Proxy {
+	case "":
+	case ProxyDirect:
+		transport.Proxy = nil
+	default:
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			// The parse error quotes the URL, which may hold a password.
+			return nil, errors.New("invalid proxy: not a URL")
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("invalid proxy %s: scheme must be http, https, socks5 or socks5h", proxyURL.Redacted())
+		}
+		if proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %s: missing host", proxyURL.Redacted())
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CAFile != "" || opts.CertFile != "" || opts.KeyFile != "" || opts.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+		if opts.CAFile != "" {
+			pem, err := os.ReadFile(opts.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read CA file: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in %s", opts.CAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if (opts.CertFile == "") != (opts.KeyFile == "") {
+			return nil, errors.New("a client certificate needs both a certificate file and a key file")
+		}
+		if opts.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("load client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/NERVEbing/model-scout/internal/platform"
 )
@@ -13,26 +14,63 @@ type Engine struct {
 	Workers  int
 }
 
-func (e Engine) Scan(ctx context.Context, excludes []string) ([]platform.ProbeResult, error) {
-	if e.Platform == nil {
-		return nil, fmt.Errorf("platform is required")
+func (e Engine) Scan(ctx context.Context, selector Selector) ([]platform.ProbeResult, error) {
+	selected, _, err := e.Select(ctx, selector)
+	if err != nil {
+		return nil, err
 	}
-	workers := e.Workers
-	if workers <= 0 {
-		workers = 1
+	return e.ProbeModels(ctx, selected)
+}
+
+func (e Engine) Select(ctx context.Context, selector Selector) ([]platform.Model, []Decision, error) {
+	if e.Platform == nil {
+		return nil, nil, fmt.Errorf("platform is required")
 	}
 
 	models, err := e.Platform.ListModels(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	filtered := make([]platform.Model, 0, len(models))
+	selected := make([]platform.Model, 0, len(models))
+	decisions := make([]Decision, 0, len(models))
 	for _, model := range models {
-		if ShouldSkip(model.ID, excludes) {
-			continue
+		decision := selector.Decide(model.ID)
+		decisions = append(decisions, decision)
+		if decision.Selected {
+			selected = append(selected, model)
 		}
-		filtered = append(filtered, model)
 	}
+	return selected, decisions, nil
+}
+
+func (e Engine) ProbeModels(ctx context.Context, models []platform.Model) ([]platform.ProbeResult, error) {
+	collected := make([]platform.ProbeResult, 0, len(models))
+	err := e.Stream(ctx, models, func(result platform.ProbeResult) error {
+		collected = append(collected, result)
+		return nil
+	})
+	return collected, err
+}
+
+// Stream probes models concurrently and calls emit with each result as soon
+// as it is available. emit is never called concurrently; if it returns an
+// error, outstanding probes are canceled and Stream returns that error. With
+// a MultiKey platform, each model is probed once per key.
+func (e Engine) Stream(ctx context.Context, models []platform.Model, emit func(platform.ProbeResult) error) error {
+	if e.Platform == nil {
+		return fmt.Errorf("platform is required")
+	}
+	if multi, ok := e.Platform.(*MultiKey); ok {
+		models = multi.Expand(models)
+	}
+	workers := e.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	jobs := make(chan platform.Model)
 	results := make(chan platform.ProbeResult)
@@ -49,7 +87,11 @@ func (e Engine) Scan(ctx context.Context, excludes []string) ([]platform.ProbeRe
 					if !ok {
 						return
 					}
+					start := time.Now()
 					result := e.Platform.Probe(ctx, model)
+					if result.LatencyMS == 0 {
+						result.LatencyMS = time.Since(start).Milliseconds()
+					}
 					select {
 					case <-ctxDone:
 						return
@@ -67,7 +109,7 @@ func (e Engine) Scan(ctx context.Context, excludes []string) ([]platform.ProbeRe
 
 	go func() {
 		defer close(jobs)
-		for _, model := range filtered {
+		for _, model := range models {
 			select {
 			case <-ctxDone:
 				return
@@ -76,20 +118,31 @@ func (e Engine) Scan(ctx context.Context, excludes []string) ([]platform.ProbeRe
 		}
 	}()
 
-	collected := make([]platform.ProbeResult, 0, len(filtered))
+	var emitErr error
 	canceled := false
+	waitDone := ctxDone
 	for {
 		select {
 		case result, ok := <-results:
 			if !ok {
-				if canceled && ctx.Err() != nil {
-					return collected, ctx.Err()
+				if emitErr != nil {
+					return emitErr
 				}
-				return collected, nil
+				if canceled && parent.Err() != nil {
+					return parent.Err()
+				}
+				return nil
+			}
+			if emitErr != nil || canceled {
+				continue
+			}
+			if err := emit(result); err != nil {
+				emitErr = err
+				cancel()
 			}
-			collected = append(collected, result)
-		case <-ctxDone:
+		case <-waitDone:
 			canceled = true
+			waitDone = nil
 		}
 	}
 }
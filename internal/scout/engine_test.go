@@ -50,8 +50,12 @@ func TestEngineScanFilters(t *testing.T) {
 		},
 	}
 
+	selector, err := NewSelector(nil, []string{"chat"}, []string{"image", "tts"})
+	if err != nil {
+		t.Fatalf("selector failed: %v", err)
+	}
 	engine := Engine{Platform: fake, Workers: 2}
-	results, err := engine.Scan(context.Background(), []string{"chat"})
+	results, err := engine.Scan(context.Background(), selector)
 	if err != nil {
 		t.Fatalf("scan failed: %v", err)
 	}
@@ -110,7 +114,7 @@ func TestEngineScanRespectsContextCancel(t *testing.T) {
 	var results []platform.ProbeResult
 	var err error
 	go func() {
-		results, err = engine.Scan(ctx, nil)
+		results, err = engine.Scan(ctx, Selector{})
 		close(done)
 	}()
 
@@ -135,3 +139,92 @@ func TestEngineScanRespectsContextCancel(t *testing.T) {
 		t.Fatalf("expected no results, got %d", len(results))
 	}
 }
+
+func TestEngineProbeModelsSkipsListing(t *testing.T) {
+	fake := &fakePlatform{
+		toReturn: []platform.Model{{ID: "listed"}},
+	}
+
+	engine := Engine{Platform: fake, Workers: 2}
+	results, err := engine.ProbeModels(context.Background(), []platform.Model{
+		{ID: "qwen-image-plus"},
+		{ID: "unlisted"},
+	})
+	if err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if fake.probed["listed"] {
+		t.Fatalf("expected listed model not to be probed")
+	}
+	if !fake.probed["qwen-image-plus"] || !fake.probed["unlisted"] {
+		t.Fatalf("expected explicit models to be probed, got %#v", fake.probed)
+	}
+}
+
+type gatedPlatform struct {
+	release chan struct{}
+}
+
+func (g *gatedPlatform) Name() string {
+	return "gated"
+}
+
+func (g *gatedPlatform) ListModels(ctx context.Context) ([]platform.Model, error) {
+	return nil, nil
+}
+
+func (g *gatedPlatform) Probe(ctx context.Context, model platform.Model) platform.ProbeResult {
+	if model.ID == "slow" {
+		select {
+		case <-g.release:
+		case <-ctx.Done():
+		}
+	}
+	return platform.ProbeResult{Platform: g.Name(), Model: model.ID, Status: "ok", Available: true}
+}
+
+func TestEngineStreamEmitsBeforeScanCompletes(t *testing.T) {
+	gated := &gatedPlatform{release: make(chan struct{})}
+	engine := Engine{Platform: gated, Workers: 2}
+
+	var emitted []string
+	err := engine.Stream(context.Background(), []platform.Model{{ID: "slow"}, {ID: "fast"}}, func(result platform.ProbeResult) error {
+		emitted = append(emitted, result.Model)
+		if result.Model == "fast" {
+			close(gated.release)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if len(emitted) != 2 || emitted[0] != "fast" || emitted[1] != "slow" {
+		t.Fatalf("expected fast before slow, got %v", emitted)
+	}
+}
+
+func TestEngineStreamStopsOnEmitError(t *testing.T) {
+	gated := &gatedPlatform{release: make(chan struct{})}
+	engine := Engine{Platform: gated, Workers: 2}
+
+	writeErr := errors.New("write failed")
+	done := make(chan error, 1)
+	go func() {
+		done <- engine.Stream(context.Background(), []platform.Model{{ID: "slow"}, {ID: "fast"}}, func(platform.ProbeResult) error {
+			return writeErr
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, writeErr) {
+			t.Fatalf("expected write error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected stream to stop after emit error")
+	}
+}
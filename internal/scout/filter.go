@@ -1,44 +1,68 @@
 package scout
 
-import "strings"
+const (
+	RuleSelected       = "selected"
+	RuleNotIncluded    = "not-included"
+	RuleExclude        = "exclude"
+	RuleDefaultExclude = "default-exclude"
+)
 
-var defaultExcludeSubstrings = []string{
-	"image",
-	"tts",
-	"asr",
-	"mt",
-	"ocr",
-	"rerank",
-	"embedding",
-	"realtime",
-	"livetranslate",
+// Selector decides which listed models are probed. Includes are evaluated
+// first: when any are set, a model must match one of them. Excludes and the
+// platform defaults are then applied to the included models.
+type Selector struct {
+	Includes []Pattern
+	Excludes []Pattern
+	Defaults []Pattern
 }
 
-func ShouldSkip(id string, excludes []string) bool {
-	candidate := strings.ToLower(id)
-	for _, substring := range defaultExcludeSubstrings {
-		if strings.Contains(candidate, substring) {
-			return true
-		}
+type Decision struct {
+	Model    string `json:"model" yaml:"model"`
+	Selected bool   `json:"selected" yaml:"selected"`
+	Rule     string `json:"rule" yaml:"rule"`
+	Pattern  string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+}
+
+func NewSelector(includes, excludes, defaults []string) (Selector, error) {
+	includePatterns, err := ParsePatterns(includes)
+	if err != nil {
+		return Selector{}, err
 	}
-	for _, substring := range excludes {
-		if substring == "" {
-			continue
-		}
-		if strings.Contains(candidate, strings.ToLower(substring)) {
-			return true
-		}
+	excludePatterns, err := ParsePatterns(excludes)
+	if err != nil {
+		return Selector{}, err
+	}
+	defaultPatterns, err := ParseDefaultPatterns(defaults)
+	if err != nil {
+		return Selector{}, err
 	}
-	return false
+	return Selector{Includes: includePatterns, Excludes: excludePatterns, Defaults: defaultPatterns}, nil
 }
 
-func FilterModels(models []string, excludes []string) []string {
-	filtered := make([]string, 0, len(models))
-	for _, modelID := range models {
-		if ShouldSkip(modelID, excludes) {
-			continue
+func (s Selector) Decide(id string) Decision {
+	decision := Decision{Model: id, Selected: true, Rule: RuleSelected}
+	if len(s.Includes) > 0 {
+		included := false
+		for _, pattern := range s.Includes {
+			if pattern.Match(id) {
+				included = true
+				decision.Pattern = pattern.String()
+				break
+			}
+		}
+		if !included {
+			return Decision{Model: id, Rule: RuleNotIncluded}
+		}
+	}
+	for _, pattern := range s.Excludes {
+		if pattern.Match(id) {
+			return Decision{Model: id, Rule: RuleExclude, Pattern: pattern.String()}
+		}
+	}
+	for _, pattern := range s.Defaults {
+		if pattern.Match(id) {
+			return Decision{Model: id, Rule: RuleDefaultExclude, Pattern: pattern.String()}
 		}
-		filtered = append(filtered, modelID)
 	}
-	return filtered
+	return decision
 }
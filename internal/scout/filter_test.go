@@ -0,0 +1,117 @@
+package scout
+
+import "testing"
+
+func TestParsePattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		id      string
+		want    bool
+	}{
+		{pattern: "plus", id: "qwen-plus", want: true},
+		{pattern: "PLUS", id: "qwen-plus", want: true},
+		{pattern: "embed", id: "text-embedding-v3", want: true},
+		{pattern: "wen", id: "qwen-plus", want: true},
+		{pattern: "qwen-*", id: "qwen-plus", want: true},
+		{pattern: "qwen-*", id: "my-qwen-plus", want: false},
+		{pattern: "qwen?-max", id: "qwen3-max", want: true},
+		{pattern: "qwen[23]-*", id: "qwen3-max", want: true},
+		{pattern: "qwen[!23]-*", id: "qwen3-max", want: false},
+		{pattern: "*/deepseek-*", id: "org/deepseek-v3", want: true},
+		{pattern: "通义*", id: "通义千问", want: true},
+		{pattern: "通义?问", id: "通义千问", want: true},
+		{pattern: "通义[千万]问", id: "通义千问", want: true},
+		{pattern: "通义*", id: "千问", want: false},
+		{pattern: "re:^qwen-(max|plus)$", id: "qwen-max", want: true},
+		{pattern: "re:^qwen-(max|plus)$", id: "qwen-max-latest", want: false},
+		{pattern: "re:\\d{4}-\\d{2}-\\d{2}", id: "qwen-plus-2025-01-25", want: true},
+	}
+	for _, tc := range cases {
+		pattern, err := ParsePattern(tc.pattern)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.pattern, err)
+		}
+		if got := pattern.Match(tc.id); got != tc.want {
+			t.Fatalf("pattern %q on %q: expected %t, got %t", tc.pattern, tc.id, tc.want, got)
+		}
+	}
+}
+
+func TestParseDefaultPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		id      string
+		want    bool
+	}{
+		{pattern: "mt", id: "qwen-mt-turbo", want: true},
+		{pattern: "mt", id: "gpt-4o-omt", want: false},
+		{pattern: "mt", id: "smtp-model", want: false},
+		{pattern: "qwen", id: "qwen3-max", want: true},
+		{pattern: "vl-72", id: "qwen2.5-VL-72b-instruct", want: true},
+		{pattern: "embed", id: "text-embedding-v3", want: false},
+		{pattern: "*-latest", id: "qwen-max-latest", want: true},
+	}
+	for _, tc := range cases {
+		pattern, err := ParseDefaultPattern(tc.pattern)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.pattern, err)
+		}
+		if got := pattern.Match(tc.id); got != tc.want {
+			t.Fatalf("default pattern %q on %q: expected %t, got %t", tc.pattern, tc.id, tc.want, got)
+		}
+	}
+}
+
+func TestParsePatternInvalid(t *testing.T) {
+	for _, raw := range []string{"re:(", "re:", "qwen-[", ""} {
+		if _, err := ParsePattern(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+	if _, err := ParseDefaultPattern("--"); err == nil {
+		t.Fatal("expected error for a default pattern without letters or digits")
+	}
+}
+
+func TestSelectorDecide(t *testing.T) {
+	selector, err := NewSelector([]string{"qwen-*", "re:^deepseek", "embed"}, []string{"*-latest", "omt"}, []string{"image", "mt"})
+	if err != nil {
+		t.Fatalf("selector failed: %v", err)
+	}
+
+	cases := []struct {
+		id       string
+		selected bool
+		rule     string
+		pattern  string
+	}{
+		{id: "qwen-plus", selected: true, rule: RuleSelected, pattern: "qwen-*"},
+		{id: "deepseek-chat", selected: true, rule: RuleSelected, pattern: "re:^deepseek"},
+		{id: "glm-4", selected: false, rule: RuleNotIncluded},
+		{id: "qwen-max-latest", selected: false, rule: RuleExclude, pattern: "*-latest"},
+		{id: "qwen-image-plus", selected: false, rule: RuleDefaultExclude, pattern: "image"},
+		{id: "qwen-mt-turbo", selected: false, rule: RuleDefaultExclude, pattern: "mt"},
+		{id: "qwen-omt-turbo", selected: false, rule: RuleExclude, pattern: "omt"},
+		{id: "qwen-smt-turbo", selected: true, rule: RuleSelected, pattern: "qwen-*"},
+		{id: "text-embedding-v3", selected: true, rule: RuleSelected, pattern: "embed"},
+	}
+	for _, tc := range cases {
+		decision := selector.Decide(tc.id)
+		if decision.Selected != tc.selected || decision.Rule != tc.rule || decision.Pattern != tc.pattern {
+			t.Fatalf("unexpected decision for %s: %#v", tc.id, decision)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("qwen2.5-VL-72b_instruct")
+	want := []string{"qwen", "2", "5", "vl", "72", "b", "instruct"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
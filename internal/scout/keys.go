@@ -0,0 +1,129 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// keyMeta is the model metadata entry that routes a probe to one key.
+const keyMeta = "scout.key"
+
+// Key is a platform instance created with one of several API keys.
+type Key struct {
+	// Label names the key in results, such as its fingerprint.
+	Label    string
+	Platform platform.Platform
+}
+
+// MultiKey is a platform that probes every model once with each key, so
+// results show which key can reach which model. Each result carries the
+// label of its key.
+type MultiKey struct {
+	keys []Key
+}
+
+// NewMultiKey combines instances of one platform created with different keys.
+func NewMultiKey(keys []Key) (*MultiKey, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.Label == "" || key.Platform == nil {
+			return nil, errors.New("every key needs a label and a platform")
+		}
+		if seen[key.Label] {
+			return nil, fmt.Errorf("duplicate key label: %s", key.Label)
+		}
+		seen[key.Label] = true
+	}
+	return &MultiKey{keys: keys}, nil
+}
+
+func (m *MultiKey) Name() string {
+	return m.keys[0].Platform.Name()
+}
+
+func (m *MultiKey) DefaultExcludes() []string {
+	return platform.DefaultExcludes(m.keys[0].Platform)
+}
+
+// Labels returns the key labels in the order they were given.
+func (m *MultiKey) Labels() []string {
+	labels := make([]string, len(m.keys))
+	for i, key := range m.keys {
+		labels[i] = key.Label
+	}
+	return labels
+}
+
+// ListModels returns the union of the models listed with each key, in the
+// order they are first seen. A key that cannot list models is still used to
+// probe; listing only fails when it fails for every key.
+func (m *MultiKey) ListModels(ctx context.Context) ([]platform.Model, error) {
+	var (
+		models []platform.Model
+		errs   []error
+		seen   = make(map[string]bool)
+	)
+	for _, key := range m.keys {
+		listed, err := key.Platform.ListModels(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("key %s: %w", key.Label, err))
+			continue
+		}
+		for _, model := range listed {
+			if !seen[model.ID] {
+				seen[model.ID] = true
+				models = append(models, model)
+			}
+		}
+	}
+	if len(errs) == len(m.keys) {
+		return nil, errors.Join(errs...)
+	}
+	return models, nil
+}
+
+// Expand returns one model per model and key. Engine.Stream expands the
+// models of a MultiKey itself.
+func (m *MultiKey) Expand(models []platform.Model) []platform.Model {
+	expanded := make([]platform.Model, 0, len(models)*len(m.keys))
+	for _, model := range models {
+		for _, key := range m.keys {
+			meta := maps.Clone(model.Meta)
+			if meta == nil {
+				meta = make(map[string]string, 1)
+			}
+			meta[keyMeta] = key.Label
+			expanded = append(expanded, platform.Model{ID: model.ID, Meta: meta})
+		}
+	}
+	return expanded
+}
+
+// Probe probes model with the key it was expanded for, or with the first key
+// if it was not expanded.
+func (m *MultiKey) Probe(ctx context.Context, model platform.Model) platform.ProbeResult {
+	key := m.keys[0]
+	if label, ok := model.Meta[keyMeta]; ok {
+		for _, candidate := range m.keys {
+			if candidate.Label == label {
+				key = candidate
+				break
+			}
+		}
+		model.Meta = maps.Clone(model.Meta)
+		delete(model.Meta, keyMeta)
+		if len(model.Meta) == 0 {
+			model.Meta = nil
+		}
+	}
+	result := key.Platform.Probe(ctx, model)
+	result.Key = key.Label
+	return result
+}
@@ -0,0 +1,95 @@
+package scout
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// keyPlatform lists its own models and can only reach the ones in allowed.
+type keyPlatform struct {
+	models  []string
+	allowed []string
+	listErr error
+}
+
+func (p keyPlatform) Name() string {
+	return "fake"
+}
+
+func (p keyPlatform) ListModels(_ context.Context) ([]platform.Model, error) {
+	if p.listErr != nil {
+		return nil, p.listErr
+	}
+	var models []platform.Model
+	for _, id := range p.models {
+		models = append(models, platform.Model{ID: id})
+	}
+	return models, nil
+}
+
+func (p keyPlatform) Probe(_ context.Context, model platform.Model) platform.ProbeResult {
+	if len(model.Meta) > 0 {
+		return platform.ProbeResult{Model: model.ID, Status: "error", Reason: "unexpected meta"}
+	}
+	if slices.Contains(p.allowed, model.ID) {
+		return platform.ProbeResult{Platform: "fake", Model: model.ID, Status: "ok", Available: true}
+	}
+	return platform.ProbeResult{Platform: "fake", Model: model.ID, Status: "fail", Reason: "403 Forbidden"}
+}
+
+func TestMultiKeyScan(t *testing.T) {
+	multi, err := NewMultiKey([]Key{
+		{Label: "cn", Platform: keyPlatform{models: []string{"qwen-plus", "qwen-max"}, allowed: []string{"qwen-plus", "qwen-max"}}},
+		{Label: "intl", Platform: keyPlatform{models: []string{"qwen-plus"}, allowed: []string{"qwen-plus"}}},
+		{Label: "revoked", Platform: keyPlatform{listErr: errors.New("401 Unauthorized")}},
+	})
+	if err != nil {
+		t.Fatalf("new multi key: %v", err)
+	}
+
+	results, err := Engine{Platform: multi, Workers: 3}.Scan(context.Background(), Selector{})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	got := make(map[string]string)
+	for _, result := range results {
+		got[result.Key+"/"+result.Model] = result.Status
+	}
+	want := map[string]string{
+		"cn/qwen-plus": "ok", "cn/qwen-max": "ok",
+		"intl/qwen-plus": "ok", "intl/qwen-max": "fail",
+		"revoked/qwen-plus": "fail", "revoked/qwen-max": "fail",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for k, status := range want {
+		if got[k] != status {
+			t.Fatalf("%s: expected %s, got %q (all: %v)", k, status, got[k], got)
+		}
+	}
+}
+
+func TestMultiKeyErrors(t *testing.T) {
+	if _, err := NewMultiKey(nil); err == nil {
+		t.Fatal("expected no keys to fail")
+	}
+	if _, err := NewMultiKey([]Key{{Label: "a", Platform: keyPlatform{}}, {Label: "a", Platform: keyPlatform{}}}); err == nil {
+		t.Fatal("expected duplicate labels to fail")
+	}
+
+	multi, err := NewMultiKey([]Key{
+		{Label: "a", Platform: keyPlatform{listErr: errors.New("401 Unauthorized")}},
+		{Label: "b", Platform: keyPlatform{listErr: errors.New("403 Forbidden")}},
+	})
+	if err != nil {
+		t.Fatalf("new multi key: %v", err)
+	}
+	if _, err := multi.ListModels(context.Background()); err == nil {
+		t.Fatal("expected listing to fail when every key fails")
+	}
+}
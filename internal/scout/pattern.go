@@ -0,0 +1,192 @@
+package scout
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type patternKind int
+
+const (
+	patternSubstring patternKind = iota
+	patternTokens
+	patternGlob
+	patternRegexp
+)
+
+const regexpPrefix = "re:"
+
+type Pattern struct {
+	raw    string
+	kind   patternKind
+	value  string
+	tokens []string
+	re     *regexp.Regexp
+}
+
+// ParsePattern accepts a plain substring, a glob containing *, ? or [...],
+// or a regular expression prefixed with "re:". Matching is case-insensitive.
+func ParsePattern(raw string) (Pattern, error) {
+	return parsePattern(raw, patternSubstring)
+}
+
+// ParseDefaultPattern is ParsePattern for the platform default excludes,
+// where plain text matches whole tokens of the model ID (see tokenize), so
+// "mt" matches "qwen-mt-turbo" but not "gpt-4o-omt".
+func ParseDefaultPattern(raw string) (Pattern, error) {
+	return parsePattern(raw, patternTokens)
+}
+
+func parsePattern(raw string, plain patternKind) (Pattern, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Pattern{}, fmt.Errorf("empty pattern")
+	}
+	if expr, ok := strings.CutPrefix(raw, regexpPrefix); ok {
+		if expr == "" {
+			return Pattern{}, fmt.Errorf("invalid pattern %q: empty regular expression", raw)
+		}
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("invalid pattern %q: %v", raw, err)
+		}
+		return Pattern{raw: raw, kind: patternRegexp, re: re}, nil
+	}
+	if strings.ContainsAny(raw, "*?[") {
+		re, err := globToRegexp(raw)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("invalid pattern %q: %v", raw, err)
+		}
+		return Pattern{raw: raw, kind: patternGlob, re: re}, nil
+	}
+	if plain == patternSubstring {
+		return Pattern{raw: raw, kind: patternSubstring, value: strings.ToLower(raw)}, nil
+	}
+	tokens := tokenize(raw)
+	if len(tokens) == 0 {
+		return Pattern{}, fmt.Errorf("invalid pattern %q: no letters or digits", raw)
+	}
+	return Pattern{raw: raw, kind: patternTokens, tokens: tokens}, nil
+}
+
+func ParsePatterns(raws []string) ([]Pattern, error) {
+	return parsePatterns(raws, ParsePattern)
+}
+
+// ParseDefaultPatterns parses default excludes with ParseDefaultPattern.
+func ParseDefaultPatterns(raws []string) ([]Pattern, error) {
+	return parsePatterns(raws, ParseDefaultPattern)
+}
+
+func parsePatterns(raws []string, parse func(string) (Pattern, error)) ([]Pattern, error) {
+	patterns := make([]Pattern, 0, len(raws))
+	for _, raw := range raws {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		pattern, err := parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+func (p Pattern) Match(id string) bool {
+	switch p.kind {
+	case patternRegexp, patternGlob:
+		return p.re.MatchString(id)
+	case patternTokens:
+		return containsTokens(tokenize(id), p.tokens)
+	default:
+		return strings.Contains(strings.ToLower(id), p.value)
+	}
+}
+
+func (p Pattern) String() string {
+	return p.raw
+}
+
+// tokenize lowercases s and splits it at every non-alphanumeric character and
+// at every boundary between letters and digits: "qwen2.5-VL-72b" becomes
+// qwen, 2, 5, vl, 72, b.
+func tokenize(s string) []string {
+	s = strings.ToLower(s)
+	var tokens []string
+	start, prev := 0, classOther
+	for i, r := range s {
+		class := charClass(r)
+		if class != prev {
+			if prev != classOther {
+				tokens = append(tokens, s[start:i])
+			}
+			start = i
+		}
+		prev = class
+	}
+	if prev != classOther {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+const (
+	classOther = iota
+	classDigit
+	classLetter
+)
+
+func charClass(r rune) int {
+	switch {
+	case unicode.IsDigit(r):
+		return classDigit
+	case unicode.IsLetter(r):
+		return classLetter
+	default:
+		return classOther
+	}
+}
+
+func containsTokens(haystack, needle []string) bool {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if slices.Equal(haystack[i:i+len(needle)], needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp translates a glob into an anchored regular expression. Unlike
+// path.Match, * also matches "/" so IDs such as "org/model" behave as expected.
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := slices.Index(runes[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := string(runes[i+1 : i+1+end])
+			if negated, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + negated
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,9 @@
+package scout
+
+import "time"
+
+// Jitter spreads d by up to ±fraction, using r in [0, 1) as the random
+// source, so several schedulers started together drift apart.
+func Jitter(d time.Duration, fraction, r float64) time.Duration {
+	return d + time.Duration((2*r-1)*fraction*float64(d))
+}
@@ -0,0 +1,18 @@
+package scout
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	if got := Jitter(10*time.Minute, 0.1, 0); got != 9*time.Minute {
+		t.Fatalf("expected lower bound, got %v", got)
+	}
+	if got := Jitter(10*time.Minute, 0.1, 0.5); got != 10*time.Minute {
+		t.Fatalf("expected unchanged interval, got %v", got)
+	}
+	if got := Jitter(10*time.Minute, 0, 0.9); got != 10*time.Minute {
+		t.Fatalf("expected no jitter, got %v", got)
+	}
+}
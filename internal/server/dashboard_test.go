@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/platform"
+	"github.com/NERVEbing/model-scout/internal/scout"
+)
+
+func TestDashboardAssets(t *testing.T) {
+	_, _, httpServer := newTestServer(t, nil)
+
+	for path, want := range map[string]string{
+		"/":          "model-scout",
+		"/app.js":    "sparkline",
+		"/style.css": "svg.spark",
+	} {
+		resp, err := http.Get(httpServer.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), want) {
+			t.Fatalf("GET %s: expected 200 containing %q, got %d", path, want, resp.StatusCode)
+		}
+	}
+}
+
+func TestHistoryPoints(t *testing.T) {
+	srv, _, httpServer := newTestServer(t, nil)
+	for range 2 {
+		if err := srv.Scan(context.Background(), ""); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+	}
+
+	var response struct {
+		Series []struct {
+			Platform string  `json:"platform"`
+			Model    string  `json:"model"`
+			Points   []Point `json:"points"`
+		} `json:"series"`
+	}
+	getJSON(t, http.MethodGet, httpServer.URL+"/history?model=qwen-max", http.StatusOK, &response)
+	if len(response.Series) != 1 {
+		t.Fatalf("expected one series, got %+v", response.Series)
+	}
+	series := response.Series[0]
+	if series.Platform != "dashscope" || series.Model != "qwen-max" || len(series.Points) != 2 || series.Points[0].Available {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+
+	getJSON(t, http.MethodGet, httpServer.URL+"/history?platform=missing", http.StatusNotFound, nil)
+}
+
+func TestHistorySeedsPoints(t *testing.T) {
+	store, err := history.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("open history: %v", err)
+	}
+	for _, at := range []time.Time{time.Now().Add(-48 * time.Hour), time.Now().Add(-time.Hour)} {
+		if _, err := store.Append(history.Scan{Time: at, Platform: "dashscope", Results: []platform.ProbeResult{
+			{Platform: "dashscope", Model: "qwen-plus", Status: "ok", Available: true, LatencyMS: 150},
+		}}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	srv, err := New(Options{Targets: []Target{{
+		Engine: scout.Engine{Platform: &fakePlatform{name: "dashscope"}},
+	}}, Interval: time.Hour, History: store})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	points := srv.targets["dashscope"].points["qwen-plus"]
+	if len(points) != 1 || points[0].LatencyMS != 150 {
+		t.Fatalf("expected only the last day's point, got %+v", points)
+	}
+}
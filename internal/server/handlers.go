@@ -0,0 +1,206 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/filter"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+type resultsResponse struct {
+	Scans   []ScanStatus           `json:"scans"`
+	Results []platform.ProbeResult `json:"results"`
+}
+
+type series struct {
+	Platform string  `json:"platform"`
+	Model    string  `json:"model"`
+	Points   []Point `json:"points"`
+}
+
+type modelResponse struct {
+	platform.ProbeResult
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /results", s.handleResults)
+	mux.HandleFunc("GET /models/{platform}/{model...}", s.handleModel)
+	mux.HandleFunc("POST /scans", s.requireToken(s.handleScan))
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("GET /history", s.handleHistory)
+	ui := dashboard()
+	for _, path := range []string{"/{$}", "/app.js", "/style.css"} {
+		mux.Handle("GET "+path, ui)
+	}
+	return mux
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "scans": s.statuses()})
+}
+
+// handleResults serves the latest results. Query parameters named after
+// filter fields match like --filter "field=a,b"; filter takes a full
+// expression. All of them must match.
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request) {
+	expr, err := queryFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	response := resultsResponse{Scans: make([]ScanStatus, 0, len(s.names)), Results: []platform.ProbeResult{}}
+	for _, name := range s.names {
+		status, results, _ := s.targets[name].snapshot()
+		response.Scans = append(response.Scans, status)
+		response.Results = append(response.Results, filter.Apply(results, expr)...)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleModel(w http.ResponseWriter, r *http.Request) {
+	targets, err := s.lookup(r.PathValue("platform"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	model := r.PathValue("model")
+	status, results, checked := targets[0].snapshot()
+	for _, result := range results {
+		if result.Model == model {
+			writeJSON(w, http.StatusOK, modelResponse{ProbeResult: result, CheckedAt: checked})
+			return
+		}
+	}
+	if status.LastSuccess.IsZero() {
+		writeError(w, http.StatusServiceUnavailable, errors.New("no completed scan yet"))
+		return
+	}
+	writeError(w, http.StatusNotFound, errors.New("model not found in the latest scan"))
+}
+
+// handleHistory serves the recent points of every model, or of ?platform=
+// and ?model=, oldest first.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	targets, err := s.lookup(r.URL.Query().Get("platform"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	model := r.URL.Query().Get("model")
+	response := struct {
+		Series []series `json:"series"`
+	}{Series: []series{}}
+	for _, t := range targets {
+		t.mu.RLock()
+		for _, name := range slices.Sorted(maps.Keys(t.points)) {
+			if model == "" || name == model {
+				response.Series = append(response.Series, series{Platform: t.name, Model: name, Points: slices.Clone(t.points[name])})
+			}
+		}
+		t.mu.RUnlock()
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleScan starts a scan of ?platform= (default: all). With ?wait=true it
+// responds once the scan has finished, including its results.
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("platform")
+	targets, err := s.lookup(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	wait, _ := strconv.ParseBool(r.URL.Query().Get("wait"))
+	if !wait {
+		s.scanAsync(r.Context(), targets)
+		writeJSON(w, http.StatusAccepted, map[string]any{"scans": s.statuses(targets...)})
+		return
+	}
+
+	if err := s.Scan(r.Context(), name); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	response := resultsResponse{Scans: s.statuses(targets...), Results: []platform.ProbeResult{}}
+	for _, t := range targets {
+		_, results, _ := t.snapshot()
+		response.Results = append(response.Results, results...)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// requireToken rejects requests without the bearer token, if one is set.
+func (s *Server) requireToken(next http.HandlerFunc) http.HandlerFunc {
+	if s.opts.Token == "" {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) statuses(targets ...*target) []ScanStatus {
+	if len(targets) == 0 {
+		targets, _ = s.lookup("")
+	}
+	statuses := make([]ScanStatus, 0, len(targets))
+	for _, t := range targets {
+		status, _, _ := t.snapshot()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func queryFilter(r *http.Request) (filter.Expr, error) {
+	var exprs []filter.Expr
+	for key, values := range r.URL.Query() {
+		if key == "filter" {
+			expr, err := filter.ParseAll(values)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+			continue
+		}
+		var split []string
+		for _, value := range values {
+			for part := range strings.SplitSeq(value, ",") {
+				split = append(split, strings.TrimSpace(part))
+			}
+		}
+		expr, err := filter.Equal(key, split...)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return filter.And(exprs...), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
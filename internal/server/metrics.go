@@ -0,0 +1,151 @@
+package server
+
+import (
+	"cmp"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NERVEbing/model-scout/internal/output"
+	"github.com/NERVEbing/model-scout/internal/platform"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the probe latency
+// histogram. Chat probes typically take between a few hundred milliseconds
+// and several seconds.
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative; the last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(seconds float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(latencyBuckets)+1)
+	}
+	i, _ := slices.BinarySearch(latencyBuckets, seconds)
+	h.counts[i]++
+	h.sum += seconds
+	h.count++
+}
+
+// metrics accumulates counters and histograms across scans. Gauges are read
+// from the targets when /metrics is served.
+type metrics struct {
+	mu      sync.Mutex
+	latency map[[2]string]*histogram // platform, model
+	probes  map[[2]string]uint64     // platform, status
+	errors  map[[2]string]uint64     // platform, error kind
+	scans   map[[2]string]uint64     // platform, result
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		latency: make(map[[2]string]*histogram),
+		probes:  make(map[[2]string]uint64),
+		errors:  make(map[[2]string]uint64),
+		scans:   make(map[[2]string]uint64),
+	}
+}
+
+func (m *metrics) observeScan(platformName string, results []platform.ProbeResult, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.scans[[2]string{platformName, "failure"}]++
+		return
+	}
+	m.scans[[2]string{platformName, "success"}]++
+	for _, result := range results {
+		m.probes[[2]string{platformName, result.Status}]++
+		if kind := platform.ErrorKind(result); kind != "" {
+			m.errors[[2]string{platformName, kind}]++
+		}
+		if result.LatencyMS > 0 {
+			key := [2]string{platformName, result.Model}
+			h := m.latency[key]
+			if h == nil {
+				h = &histogram{}
+				m.latency[key] = h
+			}
+			h.observe(float64(result.LatencyMS) / 1000)
+		}
+	}
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	var b strings.Builder
+
+	output.WriteMetricHeader(&b, "model_scout_model_available", "gauge", "Whether the model answered its latest probe (1) or not (0).")
+	for _, name := range s.names {
+		_, results, _ := s.targets[name].snapshot()
+		for _, result := range results {
+			available := "0"
+			if result.Available {
+				available = "1"
+			}
+			output.WriteMetricSample(&b, "model_scout_model_available", [][2]string{{"platform", name}, {"model", result.Model}}, available)
+		}
+	}
+
+	output.WriteMetricHeader(&b, "model_scout_scan_duration_seconds", "gauge", "Duration of the latest scan in seconds.")
+	for _, name := range s.names {
+		if status, _, _ := s.targets[name].snapshot(); !status.LastScan.IsZero() {
+			output.WriteMetricSample(&b, "model_scout_scan_duration_seconds", [][2]string{{"platform", name}}, output.FormatMetricFloat(float64(status.DurationMS)/1000))
+		}
+	}
+
+	output.WriteMetricHeader(&b, "model_scout_last_successful_scan_timestamp_seconds", "gauge", "Unix time the latest successful scan started.")
+	for _, name := range s.names {
+		if status, _, _ := s.targets[name].snapshot(); !status.LastSuccess.IsZero() {
+			output.WriteMetricSample(&b, "model_scout_last_successful_scan_timestamp_seconds", [][2]string{{"platform", name}}, strconv.FormatInt(status.LastSuccess.Unix(), 10))
+		}
+	}
+
+	s.metrics.mu.Lock()
+	writeCounters(&b, "model_scout_scans_total", "Scans by result (success or failure).", "result", s.metrics.scans)
+	writeCounters(&b, "model_scout_probes_total", "Probes by status.", "status", s.metrics.probes)
+	writeCounters(&b, "model_scout_probe_errors_total", "Failed probes by error kind.", "kind", s.metrics.errors)
+	writeHistograms(&b, "model_scout_probe_duration_seconds", "Probe round-trip time in seconds.", s.metrics.latency)
+	s.metrics.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+func sortedKeys[V any](m map[[2]string]V) [][2]string {
+	return slices.SortedFunc(maps.Keys(m), func(a, b [2]string) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+}
+
+func writeCounters(b *strings.Builder, name, help, label string, counters map[[2]string]uint64) {
+	output.WriteMetricHeader(b, name, "counter", help)
+	for _, key := range sortedKeys(counters) {
+		output.WriteMetricSample(b, name, [][2]string{{"platform", key[0]}, {label, key[1]}}, strconv.FormatUint(counters[key], 10))
+	}
+}
+
+func writeHistograms(b *strings.Builder, name, help string, histograms map[[2]string]*histogram) {
+	output.WriteMetricHeader(b, name, "histogram", help)
+	for _, key := range sortedKeys(histograms) {
+		h := histograms[key]
+		var cumulative uint64
+		for i, count := range h.counts {
+			cumulative += count
+			le := "+Inf"
+			if i < len(latencyBuckets) {
+				le = output.FormatMetricFloat(latencyBuckets[i])
+			}
+			output.WriteMetricSample(b, name+"_bucket", [][2]string{{"platform", key[0]}, {"model", key[1]}, {"le", le}}, strconv.FormatUint(cumulative, 10))
+		}
+		labels := [][2]string{{"platform", key[0]}, {"model", key[1]}}
+		output.WriteMetricSample(b, name+"_sum", labels, output.FormatMetricFloat(h.sum))
+		output.WriteMetricSample(b, name+"_count", labels, strconv.FormatUint(h.count, 10))
+	}
+}
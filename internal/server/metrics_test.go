@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	srv, fake, httpServer := newTestServer(t, nil)
+	for range 2 {
+		if err := srv.Scan(context.Background(), ""); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+	}
+	fake.fail.Store(true)
+	if err := srv.Scan(context.Background(), ""); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+
+	resp, err := http.Get(httpServer.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type: %s", resp.Header.Get("Content-Type"))
+	}
+	body := string(data)
+	for _, want := range []string{
+		`model_scout_model_available{platform="dashscope",model="qwen-max"} 0`,
+		`model_scout_model_available{platform="dashscope",model="qwen-plus"} 1`,
+		`model_scout_scans_total{platform="dashscope",result="failure"} 1`,
+		`model_scout_scans_total{platform="dashscope",result="success"} 2`,
+		`model_scout_probes_total{platform="dashscope",status="ok"} 4`,
+		`model_scout_probe_errors_total{platform="dashscope",kind="forbidden"} 2`,
+		`model_scout_probe_duration_seconds_bucket{platform="dashscope",model="qwen-max",le="0.1"} 2`,
+		`model_scout_probe_duration_seconds_bucket{platform="dashscope",model="qwen-plus",le="0.1"} 0`,
+		`model_scout_probe_duration_seconds_bucket{platform="dashscope",model="qwen-plus",le="0.25"} 2`,
+		`model_scout_probe_duration_seconds_bucket{platform="dashscope",model="qwen-plus",le="+Inf"} 2`,
+		`model_scout_probe_duration_seconds_sum{platform="dashscope",model="qwen-plus"} 0.4`,
+		`model_scout_probe_duration_seconds_count{platform="dashscope",model="qwen-plus"} 2`,
+		`model_scout_last_successful_scan_timestamp_seconds{platform="dashscope"} `,
+		`model_scout_scan_duration_seconds{platform="dashscope"} `,
+		"# TYPE model_scout_probe_duration_seconds histogram",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, body)
+		}
+	}
+
+	// Every sample must follow its own family's TYPE line.
+	family := ""
+	for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		if name, ok := strings.CutPrefix(line, "# TYPE "); ok {
+			family, _, _ = strings.Cut(name, " ")
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, family) {
+			t.Fatalf("sample %q outside its family %q", line, family)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/history"
+	"github.com/NERVEbing/model-scout/internal/platform"
+	"github.com/NERVEbing/model-scout/internal/scout"
+)
+
+// Target is a platform scanned by the server.
+type Target struct {
+	Engine   scout.Engine
+	Selector scout.Selector
+	// Record, if set, is called with every successful scan, e.g. to append it
+	// to the history.
+	Record func(started time.Time, results []platform.ProbeResult) error
+}
+
+type Options struct {
+	Targets  []Target
+	Interval time.Duration
+	// Jitter randomizes each interval by up to this fraction.
+	Jitter float64
+	// History, if set, seeds the recent points served by /history with the
+	// last day of recorded scans.
+	History *history.Store
+	// Log receives scan failures. Defaults to io.Discard.
+	Log io.Writer
+	// Token, if set, must be sent as "Authorization: Bearer <token>" to
+	// start scans with POST /scans.
+	Token string
+}
+
+// Point is one probe of a model, as drawn in the dashboard sparklines.
+type Point struct {
+	Time      time.Time `json:"time"`
+	Available bool      `json:"available"`
+	LatencyMS int64     `json:"latency_ms"`
+}
+
+// maxPoints caps the recent points kept per model.
+const maxPoints = 96
+
+// ScanStatus describes the latest scan of a platform.
+type ScanStatus struct {
+	Platform    string    `json:"platform"`
+	Scanning    bool      `json:"scanning"`
+	LastScan    time.Time `json:"last_scan,omitzero"`
+	LastSuccess time.Time `json:"last_success,omitzero"`
+	DurationMS  int64     `json:"duration_ms,omitempty"`
+	Models      int       `json:"models"`
+	Available   int       `json:"available"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Server scans its targets on a schedule and serves the latest results.
+type Server struct {
+	opts    Options
+	targets map[string]*target
+	names   []string
+	metrics *metrics
+}
+
+type target struct {
+	Target
+	name string
+	// scanMu serializes scans of the platform; mu guards the fields below.
+	scanMu  sync.Mutex
+	mu      sync.RWMutex
+	status  ScanStatus
+	results []platform.ProbeResult
+	checked time.Time
+	points  map[string][]Point
+}
+
+func New(opts Options) (*Server, error) {
+	if len(opts.Targets) == 0 {
+		return nil, errors.New("no platforms to serve")
+	}
+	if opts.Log == nil {
+		opts.Log = io.Discard
+	}
+	s := &Server{opts: opts, targets: make(map[string]*target), metrics: newMetrics()}
+	for _, t := range opts.Targets {
+		if t.Engine.Platform == nil {
+			return nil, errors.New("platform is required")
+		}
+		name := strings.ToLower(t.Engine.Platform.Name())
+		if _, ok := s.targets[name]; ok {
+			return nil, fmt.Errorf("platform %s is listed twice", name)
+		}
+		s.targets[name] = &target{Target: t, name: name, status: ScanStatus{Platform: name}, points: make(map[string][]Point)}
+		s.names = append(s.names, name)
+	}
+	slices.Sort(s.names)
+	if opts.History != nil {
+		scans, err := opts.History.Query(history.Query{Since: time.Now().Add(-24 * time.Hour)})
+		if err != nil {
+			return nil, err
+		}
+		for _, scan := range scans {
+			if t, ok := s.targets[strings.ToLower(scan.Platform)]; ok {
+				t.addPoints(scan.Time, scan.Results)
+			}
+		}
+	}
+	return s, nil
+}
+
+// Run scans every platform immediately and then every interval until ctx is
+// canceled.
+func (s *Server) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, name := range s.names {
+		t := s.targets[name]
+		wg.Go(func() {
+			for {
+				s.scan(ctx, t)
+				timer := time.NewTimer(scout.Jitter(s.opts.Interval, s.opts.Jitter, rand.Float64()))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// ListenAndServe runs the scheduler and serves HTTP on addr until ctx is
+// canceled, then shuts down gracefully.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Go(func() { s.Run(ctx) })
+	wg.Go(func() {
+		<-ctx.Done()
+		shutdownCtx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		httpServer.Shutdown(shutdownCtx)
+	})
+
+	err := httpServer.ListenAndServe()
+	cancel()
+	wg.Wait()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Scan scans the named platform, or every platform when name is empty, and
+// waits for the scans to finish.
+func (s *Server) Scan(ctx context.Context, name string) error {
+	targets, err := s.lookup(name)
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Go(func() { s.scan(ctx, t) })
+	}
+	wg.Wait()
+	return nil
+}
+
+// scanAsync starts scans in the background unless one is already running and
+// returns immediately. The scans outlive ctx's cancellation, which ends with
+// the triggering request.
+func (s *Server) scanAsync(ctx context.Context, targets []*target) {
+	ctx = context.WithoutCancel(ctx)
+	for _, t := range targets {
+		if !t.scanMu.TryLock() {
+			continue
+		}
+		t.setScanning()
+		go func() {
+			defer t.scanMu.Unlock()
+			s.scanLocked(ctx, t)
+		}()
+	}
+}
+
+func (s *Server) scan(ctx context.Context, t *target) {
+	t.scanMu.Lock()
+	defer t.scanMu.Unlock()
+	t.setScanning()
+	s.scanLocked(ctx, t)
+}
+
+func (s *Server) scanLocked(ctx context.Context, t *target) {
+	started := time.Now()
+	results, err := t.Engine.Scan(ctx, t.Selector)
+	if err == nil && t.Record != nil {
+		if recordErr := t.Record(started, results); recordErr != nil {
+			fmt.Fprintf(s.opts.Log, "%s: record history: %v\n", t.name, recordErr)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(s.opts.Log, "%s: scan failed: %v\n", t.name, err)
+	}
+	s.metrics.observeScan(t.name, results, err)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.Scanning = false
+	t.status.LastScan = started
+	t.status.DurationMS = time.Since(started).Milliseconds()
+	if err != nil {
+		// Keep the previous results: a failed scan says nothing about models.
+		t.status.Error = err.Error()
+		return
+	}
+	slices.SortFunc(results, func(a, b platform.ProbeResult) int {
+		return cmp.Compare(a.Model, b.Model)
+	})
+	t.results = results
+	t.checked = started
+	t.addPoints(started, results)
+	t.status.Error = ""
+	t.status.LastSuccess = started
+	t.status.Models = len(results)
+	t.status.Available = 0
+	for _, result := range results {
+		if result.Available {
+			t.status.Available++
+		}
+	}
+}
+
+// addPoints appends a point per result. The caller must hold t.mu or own t
+// exclusively.
+func (t *target) addPoints(at time.Time, results []platform.ProbeResult) {
+	for _, result := range results {
+		points := append(t.points[result.Model], Point{Time: at, Available: result.Available, LatencyMS: result.LatencyMS})
+		if len(points) > maxPoints {
+			points = slices.Clone(points[len(points)-maxPoints:])
+		}
+		t.points[result.Model] = points
+	}
+}
+
+func (t *target) setScanning() {
+	t.mu.Lock()
+	t.status.Scanning = true
+	t.mu.Unlock()
+}
+
+func (t *target) snapshot() (ScanStatus, []platform.ProbeResult, time.Time) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.status, t.results, t.checked
+}
+
+// errUnknownPlatform is reported as 404 by the handlers.
+var errUnknownPlatform = errors.New("unknown platform")
+
+func (s *Server) lookup(name string) ([]*target, error) {
+	if name == "" {
+		targets := make([]*target, 0, len(s.names))
+		for _, n := range s.names {
+			targets = append(targets, s.targets[n])
+		}
+		return targets, nil
+	}
+	t, ok := s.targets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (serving %s)", errUnknownPlatform, name, strings.Join(s.names, ", "))
+	}
+	return []*target{t}, nil
+}
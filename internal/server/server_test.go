@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+	"github.com/NERVEbing/model-scout/internal/scout"
+)
+
+type fakePlatform struct {
+	name  string
+	scans atomic.Int32
+	fail  atomic.Bool
+}
+
+func (p *fakePlatform) Name() string {
+	return p.name
+}
+
+func (p *fakePlatform) ListModels(_ context.Context) ([]platform.Model, error) {
+	p.scans.Add(1)
+	if p.fail.Load() {
+		return nil, context.DeadlineExceeded
+	}
+	return []platform.Model{{ID: "qwen-plus"}, {ID: "qwen-max"}, {ID: "org/model"}}, nil
+}
+
+func (p *fakePlatform) Probe(_ context.Context, model platform.Model) platform.ProbeResult {
+	if model.ID == "qwen-max" {
+		return platform.ProbeResult{Platform: p.name, Model: model.ID, Status: "fail", Reason: "403 Forbidden", LatencyMS: 10}
+	}
+	return platform.ProbeResult{Platform: p.name, Model: model.ID, Status: "ok", Available: true, LatencyMS: 200}
+}
+
+func newTestServer(t *testing.T, record func(time.Time, []platform.ProbeResult) error) (*Server, *fakePlatform, *httptest.Server) {
+	t.Helper()
+	fake := &fakePlatform{name: "dashscope"}
+	srv, err := New(Options{Targets: []Target{{
+		Engine: scout.Engine{Platform: fake, Workers: 2},
+		Record: record,
+	}}, Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	httpServer := httptest.NewServer(srv.Handler())
+	t.Cleanup(httpServer.Close)
+	return srv, fake, httpServer
+}
+
+func getJSON(t *testing.T, method, url string, wantStatus int, into any) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s: expected status %d, got %d", method, url, wantStatus, resp.StatusCode)
+	}
+	if into != nil {
+		if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+			t.Fatalf("decode %s: %v", url, err)
+		}
+	}
+}
+
+func TestResultsAndModels(t *testing.T) {
+	srv, _, httpServer := newTestServer(t, nil)
+
+	getJSON(t, http.MethodGet, httpServer.URL+"/models/dashscope/qwen-plus", http.StatusServiceUnavailable, nil)
+	if err := srv.Scan(context.Background(), ""); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+
+	var all resultsResponse
+	getJSON(t, http.MethodGet, httpServer.URL+"/results", http.StatusOK, &all)
+	if len(all.Results) != 3 || len(all.Scans) != 1 || all.Scans[0].Models != 3 || all.Scans[0].Available != 2 || all.Scans[0].LastSuccess.IsZero() {
+		t.Fatalf("unexpected results: %+v", all)
+	}
+
+	var filtered resultsResponse
+	getJSON(t, http.MethodGet, httpServer.URL+"/results?available=true&platform=dashscope,deepseek", http.StatusOK, &filtered)
+	if len(filtered.Results) != 2 {
+		t.Fatalf("expected available results, got %+v", filtered.Results)
+	}
+	getJSON(t, http.MethodGet, httpServer.URL+"/results?filter=latency+%3C+100", http.StatusOK, &filtered)
+	if len(filtered.Results) != 1 || filtered.Results[0].Model != "qwen-max" {
+		t.Fatalf("expected fast result, got %+v", filtered.Results)
+	}
+	getJSON(t, http.MethodGet, httpServer.URL+"/results?colour=red", http.StatusBadRequest, nil)
+	getJSON(t, http.MethodGet, httpServer.URL+"/results?filter=latency+%3C", http.StatusBadRequest, nil)
+
+	var model modelResponse
+	getJSON(t, http.MethodGet, httpServer.URL+"/models/DashScope/org/model", http.StatusOK, &model)
+	if model.Model != "org/model" || !model.Available || model.CheckedAt.IsZero() {
+		t.Fatalf("unexpected model: %+v", model)
+	}
+	getJSON(t, http.MethodGet, httpServer.URL+"/models/dashscope/missing", http.StatusNotFound, nil)
+	getJSON(t, http.MethodGet, httpServer.URL+"/models/openai/gpt-4o", http.StatusNotFound, nil)
+
+	var health map[string]any
+	getJSON(t, http.MethodGet, httpServer.URL+"/healthz", http.StatusOK, &health)
+	if health["status"] != "ok" {
+		t.Fatalf("unexpected health: %+v", health)
+	}
+}
+
+func TestTriggerScan(t *testing.T) {
+	_, fake, httpServer := newTestServer(t, nil)
+
+	var waited resultsResponse
+	getJSON(t, http.MethodPost, httpServer.URL+"/scans?platform=dashscope&wait=true", http.StatusOK, &waited)
+	if len(waited.Results) != 3 || fake.scans.Load() != 1 {
+		t.Fatalf("unexpected scan response: %+v", waited)
+	}
+
+	getJSON(t, http.MethodPost, httpServer.URL+"/scans", http.StatusAccepted, nil)
+	deadline := time.Now().Add(5 * time.Second)
+	for fake.scans.Load() != 2 || scanning(t, httpServer.URL) {
+		if time.Now().After(deadline) {
+			t.Fatalf("background scan did not finish")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	getJSON(t, http.MethodPost, httpServer.URL+"/scans?platform=openai", http.StatusNotFound, nil)
+	getJSON(t, http.MethodGet, httpServer.URL+"/scans", http.StatusMethodNotAllowed, nil)
+}
+
+func TestScanToken(t *testing.T) {
+	fake := &fakePlatform{name: "dashscope"}
+	srv, err := New(Options{Targets: []Target{{Engine: scout.Engine{Platform: fake}}}, Interval: time.Hour, Token: "secret"})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	httpServer := httptest.NewServer(srv.Handler())
+	defer httpServer.Close()
+
+	for _, header := range []string{"", "Bearer wrong", "secret"} {
+		req, _ := http.NewRequest(http.MethodPost, httpServer.URL+"/scans?wait=true", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != "Bearer" {
+			t.Fatalf("authorization %q: expected 401, got %d", header, resp.StatusCode)
+		}
+	}
+	if fake.scans.Load() != 0 {
+		t.Fatalf("unauthorized requests started %d scans", fake.scans.Load())
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, httpServer.URL+"/scans?wait=true", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || fake.scans.Load() != 1 {
+		t.Fatalf("expected an authorized scan, got %d", resp.StatusCode)
+	}
+	getJSON(t, http.MethodGet, httpServer.URL+"/results", http.StatusOK, nil)
+}
+
+func scanning(t *testing.T, url string) bool {
+	var health struct {
+		Scans []ScanStatus `json:"scans"`
+	}
+	getJSON(t, http.MethodGet, url+"/healthz", http.StatusOK, &health)
+	return health.Scans[0].Scanning
+}
+
+func TestFailedScanKeepsResults(t *testing.T) {
+	var recorded atomic.Int32
+	srv, fake, httpServer := newTestServer(t, func(time.Time, []platform.ProbeResult) error {
+		recorded.Add(1)
+		return nil
+	})
+	if err := srv.Scan(context.Background(), ""); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	fake.fail.Store(true)
+	if err := srv.Scan(context.Background(), "dashscope"); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+
+	var all resultsResponse
+	getJSON(t, http.MethodGet, httpServer.URL+"/results", http.StatusOK, &all)
+	if len(all.Results) != 3 || !strings.Contains(all.Scans[0].Error, "deadline") {
+		t.Fatalf("expected previous results with scan error, got %+v", all)
+	}
+	if recorded.Load() != 1 {
+		t.Fatalf("expected only the successful scan to be recorded, got %d", recorded.Load())
+	}
+}
+
+func TestRunScansOnSchedule(t *testing.T) {
+	fake := &fakePlatform{name: "dashscope"}
+	srv, err := New(Options{Targets: []Target{{Engine: scout.Engine{Platform: fake}}}, Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		srv.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for fake.scans.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected repeated scans, got %d", fake.scans.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestNewValidatesTargets(t *testing.T) {
+	if _, err := New(Options{}); err == nil {
+		t.Fatalf("expected error without targets")
+	}
+	fake := &fakePlatform{name: "dashscope"}
+	targets := []Target{{Engine: scout.Engine{Platform: fake}}, {Engine: scout.Engine{Platform: fake}}}
+	if _, err := New(Options{Targets: targets}); err == nil {
+		t.Fatalf("expected duplicate platform error")
+	}
+}
@@ -0,0 +1,174 @@
+"use strict";
+
+const refreshInterval = 30000;
+const state = { scans: [], results: [], series: new Map() };
+
+const $ = (id) => document.getElementById(id);
+
+function el(tag, attrs = {}, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs)) {
+    if (key === "class") node.className = value;
+    else node.setAttribute(key, value);
+  }
+  for (const child of children) {
+    node.append(child);
+  }
+  return node;
+}
+
+function formatLatency(ms) {
+  if (!ms || ms <= 0) return "-";
+  return ms < 1000 ? `${ms}ms` : `${(ms / 1000).toFixed(2)}s`;
+}
+
+function formatTime(value) {
+  if (!value) return "never";
+  return new Date(value).toLocaleString();
+}
+
+function cellClass(result) {
+  if (result.available) return "cell-ok";
+  return result.status === "error" ? "cell-error" : "cell-fail";
+}
+
+// sparkline draws latency over time; failed probes are red dots on the
+// baseline so outages stand out even without a latency.
+function sparkline(points) {
+  const ns = "http://www.w3.org/2000/svg";
+  const width = 80;
+  const height = 20;
+  const svg = document.createElementNS(ns, "svg");
+  svg.setAttribute("class", "spark");
+  svg.setAttribute("width", width);
+  svg.setAttribute("height", height);
+  svg.setAttribute("viewBox", `0 0 ${width} ${height}`);
+  if (!points || points.length < 2) return svg;
+
+  const max = Math.max(1, ...points.map((p) => (p.available ? p.latency_ms : 0)));
+  const x = (i) => (i / (points.length - 1)) * (width - 2) + 1;
+  const y = (ms) => height - 2 - (ms / max) * (height - 4);
+  const line = [];
+  points.forEach((p, i) => {
+    if (p.available) {
+      line.push(`${x(i).toFixed(1)},${y(p.latency_ms).toFixed(1)}`);
+      return;
+    }
+    const dot = document.createElementNS(ns, "circle");
+    dot.setAttribute("cx", x(i).toFixed(1));
+    dot.setAttribute("cy", height - 2);
+    dot.setAttribute("r", 1.5);
+    svg.append(dot);
+  });
+  const polyline = document.createElementNS(ns, "polyline");
+  polyline.setAttribute("points", line.join(" "));
+  svg.prepend(polyline);
+  return svg;
+}
+
+function render() {
+  const query = $("filter").value.trim().toLowerCase();
+  const show = $("show").value;
+  const platforms = state.scans.map((scan) => scan.platform);
+  const byKey = new Map(state.results.map((r) => [`${r.platform}/${r.model}`, r]));
+
+  const models = [...new Set(state.results.map((r) => r.model))].sort().filter((model) => {
+    if (query && !model.toLowerCase().includes(query)) return false;
+    const results = platforms.map((p) => byKey.get(`${p}/${model}`)).filter(Boolean);
+    if (show === "available") return results.some((r) => r.available);
+    if (show === "unavailable") return results.some((r) => !r.available);
+    return true;
+  });
+
+  const head = el("tr", {}, el("th", {}, "Model"));
+  for (const scan of state.scans) {
+    let detail = `checked ${formatTime(scan.last_success)}`;
+    if (scan.scanning) detail = "scanning…";
+    else if (scan.error) detail = `last scan failed: ${scan.error}`;
+    head.append(el("th", { title: detail }, scan.platform, el("small", {}, detail)));
+  }
+  $("grid").tHead.replaceChildren(head);
+
+  const rows = models.map((model) => {
+    const row = el("tr", {}, el("th", {}, model));
+    for (const platform of platforms) {
+      const result = byKey.get(`${platform}/${model}`);
+      if (!result) {
+        row.append(el("td", { class: "empty" }, "—"));
+        continue;
+      }
+      const title = [result.status, result.reason].filter(Boolean).join(": ");
+      const cell = el("div", { class: "cell" },
+        el("span", {}, el("span", { class: "status" }, result.status), " ",
+          el("span", { class: "latency" }, formatLatency(result.latency_ms))),
+        sparkline(state.series.get(`${platform}/${model}`)));
+      row.append(el("td", { class: cellClass(result), title }, cell));
+    }
+    return row;
+  });
+  if (rows.length === 0) {
+    rows.push(el("tr", {}, el("td", { class: "empty", colspan: platforms.length + 1 }, "No models match.")));
+  }
+  $("grid").tBodies[0].replaceChildren(...rows);
+
+  const available = state.results.filter((r) => r.available).length;
+  $("updated").textContent = `${available} of ${state.results.length} models available · refreshed ${new Date().toLocaleTimeString()}`;
+}
+
+async function fetchJSON(url, options = {}) {
+  const token = sessionStorage.getItem("token");
+  if (token) options.headers = { Authorization: `Bearer ${token}` };
+  const response = await fetch(url, options);
+  const body = await response.json();
+  if (!response.ok) {
+    const error = new Error(body.error || response.statusText);
+    error.status = response.status;
+    throw error;
+  }
+  return body;
+}
+
+async function refresh() {
+  try {
+    const [results, history] = await Promise.all([fetchJSON("results"), fetchJSON("history")]);
+    state.scans = results.scans;
+    state.results = results.results;
+    state.series = new Map(history.series.map((s) => [`${s.platform}/${s.model}`, s.points]));
+    $("message").textContent = "";
+    $("message").classList.remove("error");
+  } catch (err) {
+    $("message").textContent = `Refresh failed: ${err.message}`;
+    $("message").classList.add("error");
+  }
+  render();
+}
+
+async function rescan() {
+  const button = $("rescan");
+  button.disabled = true;
+  $("message").textContent = "Scanning…";
+  try {
+    try {
+      await fetchJSON("scans?wait=true", { method: "POST" });
+    } catch (err) {
+      // The server was started with a token: ask for it once per tab.
+      if (err.status !== 401) throw err;
+      const token = prompt("Token to start scans:");
+      if (!token) throw err;
+      sessionStorage.setItem("token", token);
+      await fetchJSON("scans?wait=true", { method: "POST" });
+    }
+    await refresh();
+  } catch (err) {
+    $("message").textContent = `Rescan failed: ${err.message}`;
+    $("message").classList.add("error");
+  } finally {
+    button.disabled = false;
+  }
+}
+
+$("filter").addEventListener("input", render);
+$("show").addEventListener("change", render);
+$("rescan").addEventListener("click", rescan);
+refresh();
+setInterval(refresh, refreshInterval);
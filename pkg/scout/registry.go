@@ -0,0 +1,84 @@
+package scout
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+	_ "github.com/NERVEbing/model-scout/internal/platform/all"
+)
+
+type (
+	// Options configure a platform created with New.
+	Options = platform.Options
+	// Descriptor describes a platform: its name, aliases, the environment
+	// variables its key is read from and how to create it.
+	Descriptor = platform.Descriptor
+	// TransportOptions configure the proxy and TLS settings of NewHTTPClient.
+	TransportOptions = platform.TransportOptions
+)
+
+// Factory creates a platform from options whose timeout has been defaulted.
+type Factory func(Options) (Platform, error)
+
+// NewHTTPClient returns a client for Options.HTTPClient that connects through
+// the proxy and with the certificates in opts.
+func NewHTTPClient(timeout time.Duration, opts TransportOptions) (*http.Client, error) {
+	return platform.NewHTTPClient(timeout, opts)
+}
+
+// Register makes a platform available to New and to the model-scout command
+// under name. It panics if the name is empty or already registered. Use
+// RegisterDescriptor to also give aliases and key environment variables.
+func Register(name string, factory Factory) {
+	if name == "" || factory == nil {
+		panic("scout: Register requires a name and a factory")
+	}
+	RegisterDescriptor(Descriptor{Name: strings.ToLower(name), New: factory})
+}
+
+// RegisterDescriptor is Register for a full descriptor. It panics if the
+// descriptor is incomplete or its name or an alias is already registered.
+func RegisterDescriptor(d Descriptor) {
+	platform.Register(d)
+}
+
+// Platforms returns the registered platform names, sorted.
+func Platforms() []string {
+	return platform.Names()
+}
+
+// Descriptors returns every registered platform, sorted by name.
+func Descriptors() []Descriptor {
+	return platform.Descriptors()
+}
+
+// Lookup finds a registered platform by name or alias, ignoring case.
+func Lookup(name string) (Descriptor, error) {
+	return platform.Lookup(name)
+}
+
+// New creates the platform registered under name or alias. An empty
+// opts.APIKey is read from the platform's environment variables, and a zero
+// opts.Timeout means DefaultTimeout.
+func New(name string, opts Options) (Platform, error) {
+	d, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	opts.APIKey = strings.TrimSpace(opts.APIKey)
+	if opts.APIKey == "" {
+		if opts.APIKey, _ = d.EnvKey(); opts.APIKey == "" {
+			if len(d.KeyEnv) == 0 {
+				return nil, errors.New("api key is required")
+			}
+			return nil, errors.New("api key missing; set Options.APIKey or " + strings.Join(d.KeyEnv, " or "))
+		}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return d.New(opts)
+}
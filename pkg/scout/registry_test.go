@@ -0,0 +1,88 @@
+package scout
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"dashscope", "DeepSeek", "bailian"} {
+		p, err := New(name, Options{APIKey: "sk-test"})
+		if err != nil {
+			t.Fatalf("new %s: %v", name, err)
+		}
+		if p.Name() == "" {
+			t.Fatalf("new %s: empty name", name)
+		}
+	}
+
+	t.Setenv("DASHSCOPE_API_KEY", "")
+	if _, err := New("dashscope", Options{APIKey: " "}); err == nil {
+		t.Fatal("expected a missing key to fail")
+	}
+	t.Setenv("DASHSCOPE_API_KEY", "sk-env")
+	if _, err := New("dashscope", Options{}); err != nil {
+		t.Fatalf("expected the key to be read from the environment, got %v", err)
+	}
+	if _, err := New("unknown", Options{APIKey: "sk-test"}); err == nil {
+		t.Fatal("expected an unknown platform to fail")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	var got Options
+	Register("Scout-Test", func(opts Options) (Platform, error) {
+		got = opts
+		return fakePlatform{}, nil
+	})
+
+	if !slices.Contains(Platforms(), "scout-test") {
+		t.Fatalf("expected scout-test in %v", Platforms())
+	}
+	if _, err := New("scout-test", Options{APIKey: "sk-test"}); err != nil {
+		t.Fatalf("new scout-test: %v", err)
+	}
+	if got.APIKey != "sk-test" || got.Timeout != DefaultTimeout {
+		t.Fatalf("unexpected options: %+v", got)
+	}
+	if _, err := New("scout-test", Options{}); err == nil {
+		t.Fatal("expected a missing key to fail")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a duplicate registration to panic")
+		}
+	}()
+	Register("scout-test", func(Options) (Platform, error) { return fakePlatform{}, nil })
+}
+
+func TestRegisterDescriptor(t *testing.T) {
+	RegisterDescriptor(Descriptor{
+		Name:    "scout-descriptor",
+		Aliases: []string{"scout-alias"},
+		KeyEnv:  []string{"SCOUT_DESCRIPTOR_API_KEY"},
+		New: func(opts Options) (Platform, error) {
+			if opts.APIKey == "sk-bad" {
+				return nil, errors.New("bad key")
+			}
+			return fakePlatform{}, nil
+		},
+	})
+
+	var found bool
+	for _, d := range Descriptors() {
+		found = found || d.Name == "scout-descriptor"
+	}
+	if !found {
+		t.Fatalf("expected scout-descriptor in %v", Platforms())
+	}
+	t.Setenv("SCOUT_DESCRIPTOR_API_KEY", "sk-env")
+	if _, err := New("scout-alias", Options{}); err != nil {
+		t.Fatalf("new scout-alias: %v", err)
+	}
+	if _, err := New("scout-descriptor", Options{APIKey: "sk-bad"}); err == nil || err.Error() != "bad key" {
+		t.Fatalf("expected the constructor error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package scout lets other Go programs list and probe models with the same
+// engine and platform drivers as the model-scout command.
+//
+//	p, err := scout.New("dashscope", scout.Options{APIKey: key})
+//	if err != nil { ... }
+//	results, err := scout.Scan(ctx, p, scout.ScanOptions{Includes: []string{"qwen-*"}})
+package scout
+
+import (
+	"context"
+	"time"
+
+	"github.com/NERVEbing/model-scout/internal/platform"
+	core "github.com/NERVEbing/model-scout/internal/scout"
+)
+
+type (
+	// Platform lists and probes the models of one provider.
+	Platform = platform.Platform
+	// DefaultExcluder is implemented by platforms that skip some models
+	// unless told otherwise.
+	DefaultExcluder = platform.DefaultExcluder
+	Model           = platform.Model
+	ProbeResult     = platform.ProbeResult
+
+	// Engine probes the models of a platform concurrently.
+	Engine = core.Engine
+	// Selector decides which listed models are probed.
+	Selector = core.Selector
+	Decision = core.Decision
+
+	// Key is a platform created with one of several API keys.
+	Key = core.Key
+	// MultiKey probes every model once with each of its keys; each result
+	// carries the key's label.
+	MultiKey = core.MultiKey
+)
+
+// Selector decision rules.
+const (
+	RuleSelected       = core.RuleSelected
+	RuleNotIncluded    = core.RuleNotIncluded
+	RuleExclude        = core.RuleExclude
+	RuleDefaultExclude = core.RuleDefaultExclude
+)
+
+const (
+	DefaultTimeout = 15 * time.Second
+	DefaultWorkers = 4
+)
+
+// ScanOptions select and probe the models of a platform.
+type ScanOptions struct {
+	// Workers is the number of concurrent probes; zero means DefaultWorkers.
+	Workers int
+	// Includes and Excludes use the same patterns as --include and
+	// --exclude.
+	Includes []string
+	Excludes []string
+	// NoDefaultExcludes also probes the models the platform skips by default.
+	NoDefaultExcludes bool
+}
+
+// NewSelector parses include, exclude and default exclude patterns.
+func NewSelector(includes, excludes, defaults []string) (Selector, error) {
+	return core.NewSelector(includes, excludes, defaults)
+}
+
+// NewMultiKey combines instances of one platform created with different
+// keys, to compare which key can reach which model.
+func NewMultiKey(keys []Key) (*MultiKey, error) {
+	return core.NewMultiKey(keys)
+}
+
+// DefaultExcludes returns the patterns p skips by default, if any.
+func DefaultExcludes(p Platform) []string {
+	return platform.DefaultExcludes(p)
+}
+
+// KeyFingerprint identifies an API key without revealing it.
+func KeyFingerprint(key string) string {
+	return platform.KeyFingerprint(key)
+}
+
+// Scan lists the models of p, selects them according to opts and probes
+// them. Results are returned in completion order.
+func Scan(ctx context.Context, p Platform, opts ScanOptions) ([]ProbeResult, error) {
+	var defaults []string
+	if !opts.NoDefaultExcludes {
+		defaults = DefaultExcludes(p)
+	}
+	selector, err := NewSelector(opts.Includes, opts.Excludes, defaults)
+	if err != nil {
+		return nil, err
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	return Engine{Platform: p, Workers: workers}.Scan(ctx, selector)
+}
@@ -0,0 +1,57 @@
+package scout
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+type fakePlatform struct{}
+
+func (fakePlatform) Name() string {
+	return "fake"
+}
+
+func (fakePlatform) ListModels(_ context.Context) ([]Model, error) {
+	return []Model{{ID: "chat-small"}, {ID: "chat-large"}, {ID: "image-gen"}, {ID: "embed-v1"}}, nil
+}
+
+func (fakePlatform) Probe(_ context.Context, model Model) ProbeResult {
+	return ProbeResult{Platform: "fake", Model: model.ID, Status: "ok", Available: true, LatencyMS: 1}
+}
+
+func (fakePlatform) DefaultExcludes() []string {
+	return []string{"image"}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ScanOptions
+		want []string
+	}{
+		{name: "default excludes", want: []string{"chat-large", "chat-small", "embed-v1"}},
+		{name: "includes and excludes", opts: ScanOptions{Includes: []string{"chat-*", "embed-*"}, Excludes: []string{"*-large"}}, want: []string{"chat-small", "embed-v1"}},
+		{name: "no default excludes", opts: ScanOptions{Workers: 1, NoDefaultExcludes: true}, want: []string{"chat-large", "chat-small", "embed-v1", "image-gen"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Scan(context.Background(), fakePlatform{}, tt.opts)
+			if err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, result.Model)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := Scan(context.Background(), fakePlatform{}, ScanOptions{Includes: []string{"re:("}}); err == nil {
+		t.Fatal("expected an invalid pattern to fail")
+	}
+}